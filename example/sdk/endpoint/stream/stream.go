@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/runpod/go-sdk/pkg/sdk"
@@ -44,12 +45,12 @@ func main() {
 		panic(err)
 	}
 
-	streamChan := make(chan rpEndpoint.StreamOutput, 100)
+	streamChan := make(chan rpEndpoint.StreamResult, 100)
 
 	err = endpoint.Stream(&rpEndpoint.StreamInput{Id: request.Id}, streamChan)
 	if err != nil {
 		// timeout reached, if we want to get the data that has been streamed
-		if err.Error() == "ctx timeout reached" {
+		if errors.Is(err, rpEndpoint.ErrTimeout) {
 			for data := range streamChan {
 				dt, _ := json.Marshal(data)
 				fmt.Printf("output:%s\n", dt)
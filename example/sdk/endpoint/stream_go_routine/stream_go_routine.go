@@ -45,7 +45,7 @@ func main() {
 		panic(err)
 	}
 
-	streamChan := make(chan rpEndpoint.StreamOutput, 100)
+	streamChan := make(chan rpEndpoint.StreamResult, 100)
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -59,7 +59,7 @@ func main() {
 	wg.Wait()
 }
 
-func processData(op chan rpEndpoint.StreamOutput, wg *sync.WaitGroup) {
+func processData(op chan rpEndpoint.StreamResult, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for data := range op {
 		dt, _ := json.Marshal(data)
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/config"
+	rpEndpoint "github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+func main() {
+
+	endpoint, err := rpEndpoint.New(
+		&config.Config{ApiKey: sdk.String("API_KEY")},
+		&rpEndpoint.Option{EndpointId: sdk.String("ENDPOINT_ID")},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+	job, err := endpoint.Submit(ctx, &rpEndpoint.RunInput{
+		JobInput: &rpEndpoint.JobInput{
+			Input: map[string]interface{}{
+				"mock_return": []string{"value1", "value2", "value3"},
+				"mock_delay":  10,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	reader, err := job.Stream(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer reader.Close()
+
+	for reader.Next() {
+		dt, _ := json.Marshal(reader.Result())
+		fmt.Printf("output:%s\n", dt)
+	}
+	if err := reader.Err(); err != nil {
+		panic(err)
+	}
+	fmt.Printf("status:%s\n", *reader.Final().Status)
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Environment variables read by FromEnv and Load.
+const (
+	EnvApiKey         = "RUNPOD_API_KEY"
+	EnvBaseURL        = "RUNPOD_ENDPOINT_BASE_URL"
+	EnvRequestTimeout = "RUNPOD_REQUEST_TIMEOUT"
+	EnvSyncTimeout    = "RUNPOD_SYNC_TIMEOUT"
+	// EnvProfile selects the profile read by Load.
+	EnvProfile = "RUNPOD_PROFILE"
+	// EnvConfigFile overrides the path of the profile file.
+	EnvConfigFile = "RUNPOD_CONFIG_FILE"
+)
+
+const DefaultProfile = "default"
+
+// Keys of a profile in the config file.
+const (
+	profileApiKey         = "api_key"
+	profileBaseURL        = "endpoint_base_url"
+	profileRequestTimeout = "request_timeout"
+	profileSyncTimeout    = "sync_timeout"
+)
+
+// FromEnv builds a Config from the environment only.
+func FromEnv() (*Config, error) {
+	cf := &Config{}
+	errs := applyEnv(cf)
+	if cf.ApiKey == nil {
+		errs = append(errs, fmt.Errorf("api key is required: %s is not set", EnvApiKey))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cf, nil
+}
+
+// Load builds a Config from the profile named by RUNPOD_PROFILE, or the
+// default profile, overridden by the environment. See LoadProfile.
+func Load() (*Config, error) {
+	return LoadProfile("")
+}
+
+// LoadProfile builds a Config from a profile of the RunPod config file,
+// ~/.runpod/config.toml or RUNPOD_CONFIG_FILE, the file the RunPod CLI
+// writes:
+//
+//	[default]
+//	api_key = "..."
+//
+//	[staging]
+//	api_key = "..."
+//	endpoint_base_url = "https://staging.example.com/v2"
+//	request_timeout = 5
+//	sync_timeout = 120
+//
+// Environment variables take precedence over the profile, and the profile
+// over the SDK defaults. An empty name selects RUNPOD_PROFILE, or "default".
+// A profile asked for by name must exist; the default one may be missing as
+// long as the environment provides an api key.
+func LoadProfile(name string) (*Config, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	explicit := name != ""
+	if !explicit {
+		name = DefaultProfile
+	}
+
+	var errs []error
+	cf := &Config{}
+
+	// missing explains why the profile provided no api key
+	var missing string
+	found := false
+	var profile map[string]interface{}
+	// without a home directory there is no config file, like when it does
+	// not exist
+	path, err := ProfilePath()
+	if err == nil {
+		profile, err = readProfile(path, name)
+	}
+	switch {
+	case path == "":
+		missing = err.Error()
+	case errors.Is(err, fs.ErrNotExist):
+		missing = fmt.Sprintf("%s does not exist", path)
+	case err != nil:
+		errs = append(errs, err)
+	case profile == nil:
+		missing = fmt.Sprintf("%s has no profile %q", path, name)
+	default:
+		found = true
+		missing = fmt.Sprintf("profile %q in %s has no %s", name, path, profileApiKey)
+		errs = append(errs, applyProfile(cf, profile, name, path)...)
+	}
+	if explicit && !found && missing != "" {
+		errs = append(errs, fmt.Errorf("profile %q not found: %s", name, missing))
+	}
+
+	errs = append(errs, applyEnv(cf)...)
+	if cf.ApiKey == nil && missing != "" && (found || !explicit) {
+		errs = append(errs, fmt.Errorf("api key is required: %s is not set and %s", EnvApiKey, missing))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cf, nil
+}
+
+// ProfilePath returns the path of the config file read by LoadProfile.
+func ProfilePath() (string, error) {
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("config file: %w", err)
+	}
+	return filepath.Join(home, ".runpod", "config.toml"), nil
+}
+
+// readProfile returns the named profile of the file at path, or nil if the
+// file has no such profile. Top level keys, as written by older versions of
+// the CLI, are read as the default profile.
+func readProfile(path string, name string) (map[string]interface{}, error) {
+	var file map[string]interface{}
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	if profile, ok := file[name].(map[string]interface{}); ok {
+		return profile, nil
+	}
+	if name != DefaultProfile {
+		return nil, nil
+	}
+	profile := map[string]interface{}{}
+	for key, value := range file {
+		if _, ok := value.(map[string]interface{}); !ok {
+			profile[key] = value
+		}
+	}
+	if key, ok := profile["apikey"]; ok {
+		profile[profileApiKey] = key
+	}
+	if len(profile) == 0 {
+		return nil, nil
+	}
+	return profile, nil
+}
+
+func applyProfile(cf *Config, profile map[string]interface{}, name string, path string) []error {
+	var errs []error
+	source := func(key string) string {
+		return fmt.Sprintf("%s of profile %q in %s", key, name, path)
+	}
+	str := func(key string) *string {
+		value, ok := profile[key]
+		if !ok {
+			return nil
+		}
+		s, ok := value.(string)
+		if !ok || s == "" {
+			errs = append(errs, fmt.Errorf("invalid %s: must be a non empty string", source(key)))
+			return nil
+		}
+		return &s
+	}
+	seconds := func(key string) *int {
+		value, ok := profile[key]
+		if !ok {
+			return nil
+		}
+		n, ok := value.(int64)
+		if !ok || n <= 0 {
+			errs = append(errs, fmt.Errorf("invalid %s: must be a positive number of seconds", source(key)))
+			return nil
+		}
+		v := int(n)
+		return &v
+	}
+
+	cf.ApiKey = str(profileApiKey)
+	cf.BaseURL = str(profileBaseURL)
+	cf.RequestTimeout = seconds(profileRequestTimeout)
+	cf.SyncTimeout = seconds(profileSyncTimeout)
+	return errs
+}
+
+// applyEnv overrides cf with the environment variables that are set.
+func applyEnv(cf *Config) []error {
+	var errs []error
+	if v := os.Getenv(EnvApiKey); v != "" {
+		cf.ApiKey = &v
+	}
+	if v := os.Getenv(EnvBaseURL); v != "" {
+		cf.BaseURL = &v
+	}
+	for _, env := range []struct {
+		name  string
+		value **int
+	}{
+		{EnvRequestTimeout, &cf.RequestTimeout},
+		{EnvSyncTimeout, &cf.SyncTimeout},
+	} {
+		v := os.Getenv(env.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %q: must be a positive number of seconds", env.name, v))
+			continue
+		}
+		*env.value = &n
+	}
+	return errs
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setup writes content to a temporary config file, when not empty, and
+// clears the RUNPOD_ environment.
+func setup(t *testing.T, content string) string {
+	t.Helper()
+	for _, env := range []string{EnvApiKey, EnvBaseURL, EnvRequestTimeout, EnvSyncTimeout, EnvProfile} {
+		t.Setenv(env, "")
+	}
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(EnvConfigFile, path)
+	return path
+}
+
+const profiles = `
+[default]
+api_key = "default-key"
+
+[staging]
+api_key = "staging-key"
+endpoint_base_url = "https://staging.example.com/v2"
+request_timeout = 5
+sync_timeout = 120
+`
+
+func TestLoadDefaultProfile(t *testing.T) {
+	setup(t, profiles)
+
+	cf, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if *cf.ApiKey != "default-key" {
+		t.Errorf("ApiKey = %q, want default-key", *cf.ApiKey)
+	}
+	if cf.BaseURL != nil || cf.RequestTimeout != nil || cf.SyncTimeout != nil {
+		t.Errorf("unset keys were filled: %+v", cf)
+	}
+}
+
+func TestLoadNamedProfile(t *testing.T) {
+	setup(t, profiles)
+
+	cf, err := LoadProfile("staging")
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if *cf.ApiKey != "staging-key" || *cf.BaseURL != "https://staging.example.com/v2" {
+		t.Errorf("got api key %q and base url %q", *cf.ApiKey, *cf.BaseURL)
+	}
+	if *cf.RequestTimeout != 5 || *cf.SyncTimeout != 120 {
+		t.Errorf("got timeouts %d and %d, want 5 and 120", *cf.RequestTimeout, *cf.SyncTimeout)
+	}
+
+	t.Setenv(EnvProfile, "staging")
+	cf, err = Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if *cf.ApiKey != "staging-key" {
+		t.Errorf("ApiKey = %q, want the %s profile", *cf.ApiKey, EnvProfile)
+	}
+}
+
+func TestLoadEnvOverridesProfile(t *testing.T) {
+	setup(t, profiles)
+	t.Setenv(EnvApiKey, "env-key")
+	t.Setenv(EnvRequestTimeout, "7")
+
+	cf, err := LoadProfile("staging")
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if *cf.ApiKey != "env-key" {
+		t.Errorf("ApiKey = %q, want env-key", *cf.ApiKey)
+	}
+	if *cf.RequestTimeout != 7 {
+		t.Errorf("RequestTimeout = %d, want 7", *cf.RequestTimeout)
+	}
+	if *cf.SyncTimeout != 120 || *cf.BaseURL != "https://staging.example.com/v2" {
+		t.Errorf("profile values not kept: sync timeout %d, base url %q", *cf.SyncTimeout, *cf.BaseURL)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	path := setup(t, "")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), path+" does not exist") {
+		t.Fatalf("err = %v, want it to name the missing file", err)
+	}
+
+	t.Setenv(EnvApiKey, "env-key")
+	cf, err := Load()
+	if err != nil {
+		t.Fatalf("Load with %s: %v", EnvApiKey, err)
+	}
+	if *cf.ApiKey != "env-key" {
+		t.Errorf("ApiKey = %q, want env-key", *cf.ApiKey)
+	}
+
+	// a profile asked for by name must exist, even with an api key in the env
+	if _, err := LoadProfile("staging"); err == nil || !strings.Contains(err.Error(), `profile "staging" not found`) {
+		t.Errorf("err = %v, want profile not found", err)
+	}
+}
+
+func TestLoadWithoutHome(t *testing.T) {
+	setup(t, "")
+	t.Setenv(EnvConfigFile, "")
+	t.Setenv("HOME", "")
+	if _, err := os.UserHomeDir(); err == nil {
+		t.Skip("the home directory does not come from HOME")
+	}
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "api key is required") {
+		t.Fatalf("err = %v, want a missing api key error", err)
+	}
+
+	t.Setenv(EnvApiKey, "env-key")
+	cf, err := Load()
+	if err != nil {
+		t.Fatalf("Load with %s: %v", EnvApiKey, err)
+	}
+	if *cf.ApiKey != "env-key" {
+		t.Errorf("ApiKey = %q, want env-key", *cf.ApiKey)
+	}
+
+	if _, err := LoadProfile("staging"); err == nil || !strings.Contains(err.Error(), `profile "staging" not found`) {
+		t.Errorf("err = %v, want profile not found", err)
+	}
+}
+
+func TestLoadMissingProfile(t *testing.T) {
+	setup(t, profiles)
+	t.Setenv(EnvApiKey, "env-key")
+
+	_, err := LoadProfile("production")
+	if err == nil || !strings.Contains(err.Error(), `has no profile "production"`) {
+		t.Fatalf("err = %v, want a missing profile error", err)
+	}
+}
+
+func TestLoadProfileWithoutApiKey(t *testing.T) {
+	setup(t, "[default]\nrequest_timeout = 5\n")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), `profile "default"`) || !strings.Contains(err.Error(), "has no api_key") {
+		t.Fatalf("err = %v, want a missing api_key error", err)
+	}
+}
+
+func TestLoadLegacyApiKey(t *testing.T) {
+	setup(t, "apikey = \"legacy-key\"\n")
+
+	cf, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if *cf.ApiKey != "legacy-key" {
+		t.Errorf("ApiKey = %q, want legacy-key", *cf.ApiKey)
+	}
+
+	if _, err := LoadProfile("staging"); err == nil {
+		t.Errorf("top level keys were read for a named profile")
+	}
+}
+
+func TestLoadInvalidTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		want    []string
+	}{
+		{
+			name:    "zero in profile",
+			content: "[default]\napi_key = \"k\"\nrequest_timeout = 0\n",
+			want:    []string{"invalid request_timeout"},
+		},
+		{
+			name:    "string in profile",
+			content: "[default]\napi_key = \"k\"\nsync_timeout = \"90\"\n",
+			want:    []string{"invalid sync_timeout"},
+		},
+		{
+			name:    "negative in env",
+			content: profiles,
+			env:     map[string]string{EnvSyncTimeout: "-1"},
+			want:    []string{"invalid " + EnvSyncTimeout},
+		},
+		{
+			name:    "both",
+			content: "[default]\napi_key = \"k\"\nrequest_timeout = -3\n",
+			env:     map[string]string{EnvRequestTimeout: "fast"},
+			want:    []string{"invalid request_timeout", "invalid " + EnvRequestTimeout},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup(t, tt.content)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			_, err := Load()
+			if err == nil {
+				t.Fatalf("Load succeeded, want %v", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("err = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	setup(t, "[default\napi_key = ")
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "config file") {
+		t.Fatalf("err = %v, want a config file error", err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	setup(t, profiles)
+
+	if _, err := FromEnv(); err == nil || !strings.Contains(err.Error(), EnvApiKey) {
+		t.Fatalf("err = %v, want a missing %s error", err, EnvApiKey)
+	}
+	t.Setenv(EnvApiKey, "env-key")
+	t.Setenv(EnvSyncTimeout, "30")
+	cf, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if *cf.ApiKey != "env-key" || *cf.SyncTimeout != 30 {
+		t.Errorf("got api key %q and sync timeout %d", *cf.ApiKey, *cf.SyncTimeout)
+	}
+}
@@ -1,5 +1,38 @@
 package config
 
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/runpod/go-sdk/pkg/sdk/ratelimit"
+)
+
 type Config struct {
-	ApiKey *string
+	ApiKey *string `required:"true"`
+
+	// BaseURL overrides the serverless API url, https://api.runpod.ai/v2, for
+	// endpoints built from this config.
+	BaseURL *string
+	// RequestTimeout is the default timeout in seconds of single requests
+	// such as Run and Status.
+	RequestTimeout *int `default:"3" min:"1"`
+	// SyncTimeout is the default timeout in seconds of RunSync, StatusSync
+	// and Stream.
+	SyncTimeout *int `default:"90" min:"1"`
+
+	// HTTPClient is used for every API call made by endpoints built from this
+	// config. When nil, each endpoint keeps its own pooled keep-alive client.
+	HTTPClient *http.Client
+	// Transport is used to build the endpoint's client when HTTPClient is nil,
+	// e.g. to add a proxy, custom TLS roots or a test transport.
+	Transport http.RoundTripper
+
+	// RateLimiter throttles the requests of every endpoint built from this
+	// config, e.g. ratelimit.New(10, 20) for one api key.
+	RateLimiter ratelimit.Limiter
+
+	// Logger receives the requests, responses and job status changes of
+	// endpoints built from this config. Secrets are redacted. The SDK does
+	// not log when nil.
+	Logger *slog.Logger
 }
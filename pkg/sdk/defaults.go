@@ -0,0 +1,194 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidInput is matched by every ValidationError.
+var ErrInvalidInput = errors.New("invalid input")
+
+// FieldError is a problem with one field of an input.
+type FieldError struct {
+	// Field is the Go name of the field, e.g. "JobInput.Policy.TTL".
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError lists every invalid field of an input.
+type ValidationError struct {
+	// Type is the name of the input's type, e.g. "StatusInput".
+	Type   string
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Type, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// ApplyDefaults fills the nil pointer fields of the struct v points to from
+// their default tag, then checks the struct's tags:
+//
+//	Id             *string `required:"true"`
+//	RequestTimeout *int    `default:"3" min:"1"`
+//
+// A required field must not be nil, nor an empty string, slice or map; min
+// bounds numbers. Tagged nested structs are checked too when set, on a copy
+// so the caller's values are never modified. Problems are reported together
+// in a *ValidationError.
+func ApplyDefaults(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("apply defaults: %T is not a pointer to a struct", v)
+	}
+	var fields []FieldError
+	if err := applyStruct(rv.Elem(), "", &fields); err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Type: rv.Elem().Type().Name(), Fields: fields}
+	}
+	return nil
+}
+
+func applyStruct(rv reflect.Value, prefix string, fields *[]FieldError) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		name := prefix + sf.Name
+
+		if def, ok := sf.Tag.Lookup("default"); ok && fv.Kind() == reflect.Pointer && fv.IsNil() {
+			value, err := parseValue(def, sf.Type.Elem())
+			if err != nil {
+				return fmt.Errorf("apply defaults: field %s: bad default tag: %w", name, err)
+			}
+			ptr := reflect.New(sf.Type.Elem())
+			ptr.Elem().Set(value)
+			fv.Set(ptr)
+		}
+
+		if sf.Tag.Get("required") == "true" && isEmpty(fv) {
+			*fields = append(*fields, FieldError{Field: name, Message: "is required"})
+			continue
+		}
+
+		elem := fv
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+		if min, ok := sf.Tag.Lookup("min"); ok {
+			if err := checkMin(elem, min, name, fields); err != nil {
+				return err
+			}
+		}
+		if elem.Kind() == reflect.Struct && fv.Kind() == reflect.Pointer && hasTags(elem.Type()) {
+			nested := reflect.New(elem.Type())
+			nested.Elem().Set(elem)
+			if err := applyStruct(nested.Elem(), name+".", fields); err != nil {
+				return err
+			}
+			fv.Set(nested)
+		}
+	}
+	return nil
+}
+
+// hasTags reports whether a struct has fields tagged for ApplyDefaults.
+func hasTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag
+		for _, key := range []string{"default", "required", "min"} {
+			if _, ok := tag.Lookup(key); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseValue(s string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	default:
+		return v, fmt.Errorf("unsupported type %s", t)
+	}
+	return v, nil
+}
+
+func checkMin(v reflect.Value, min string, name string, fields *[]FieldError) error {
+	bound, err := strconv.ParseFloat(min, 64)
+	if err != nil {
+		return fmt.Errorf("apply defaults: field %s: bad min tag: %w", name, err)
+	}
+	var n float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return fmt.Errorf("apply defaults: field %s: min tag on %s", name, v.Type())
+	}
+	if n < bound {
+		*fields = append(*fields, FieldError{Field: name, Message: "must be at least " + min})
+	}
+	return nil
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.String {
+			return v.Elem().Len() == 0
+		}
+		return false
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
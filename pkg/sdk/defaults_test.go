@@ -0,0 +1,189 @@
+package sdk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type nestedInput struct {
+	TTL     *int    `default:"60" min:"1"`
+	Name    *string `required:"true"`
+	Comment *string
+}
+
+type testInput struct {
+	Id      *string           `required:"true"`
+	Timeout *int              `default:"3" min:"1"`
+	Ratio   *float64          `default:"0.5" min:"0"`
+	Follow  *bool             `default:"true"`
+	Label   *string           `default:"runpod"`
+	Tags    []string          `required:"true"`
+	Input   map[string]string `required:"true"`
+	Nested  *nestedInput      `json:"nested"`
+	Plain   *struct{ Value *int }
+	Extra   map[string]float64 // untagged
+	private *int               `default:"1"`
+}
+
+func validInput() testInput {
+	return testInput{
+		Id:    String("job"),
+		Tags:  []string{"a"},
+		Input: map[string]string{"k": "v"},
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	in := validInput()
+	if err := ApplyDefaults(&in); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	if in.Timeout == nil || *in.Timeout != 3 {
+		t.Errorf("Timeout = %v, want 3", in.Timeout)
+	}
+	if in.Ratio == nil || *in.Ratio != 0.5 {
+		t.Errorf("Ratio = %v, want 0.5", in.Ratio)
+	}
+	if in.Follow == nil || !*in.Follow {
+		t.Errorf("Follow = %v, want true", in.Follow)
+	}
+	if in.Label == nil || *in.Label != "runpod" {
+		t.Errorf("Label = %v, want runpod", in.Label)
+	}
+	if in.Nested != nil {
+		t.Errorf("Nested = %v, want nil", in.Nested)
+	}
+	if in.private != nil {
+		t.Errorf("unexported field was set")
+	}
+}
+
+func TestApplyDefaultsKeepsValues(t *testing.T) {
+	in := validInput()
+	in.Timeout = Int(10)
+	in.Follow = Bool(false)
+	in.Label = String("")
+	if err := ApplyDefaults(&in); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	if *in.Timeout != 10 || *in.Follow || *in.Label != "" {
+		t.Errorf("set values were overwritten: %d %v %q", *in.Timeout, *in.Follow, *in.Label)
+	}
+}
+
+func TestApplyDefaultsNestedCopy(t *testing.T) {
+	nested := &nestedInput{Name: String("n")}
+	in := validInput()
+	in.Nested = nested
+	if err := ApplyDefaults(&in); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	if in.Nested == nested {
+		t.Fatalf("nested struct was not copied")
+	}
+	if in.Nested.TTL == nil || *in.Nested.TTL != 60 {
+		t.Errorf("Nested.TTL = %v, want 60", in.Nested.TTL)
+	}
+	if nested.TTL != nil {
+		t.Errorf("caller's nested struct was modified: TTL = %d", *nested.TTL)
+	}
+	if in.Nested.Name != nested.Name {
+		t.Errorf("nested values were not kept")
+	}
+}
+
+func TestApplyDefaultsUntaggedNested(t *testing.T) {
+	plain := &struct{ Value *int }{}
+	in := validInput()
+	in.Plain = plain
+	if err := ApplyDefaults(&in); err != nil {
+		t.Fatalf("ApplyDefaults: %v", err)
+	}
+	if in.Plain != plain {
+		t.Errorf("untagged nested struct was copied")
+	}
+}
+
+func TestApplyDefaultsValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*testInput)
+		fields []string
+	}{
+		{"nil required", func(in *testInput) { in.Id = nil }, []string{"Id is required"}},
+		{"empty string", func(in *testInput) { in.Id = String("") }, []string{"Id is required"}},
+		{"empty slice", func(in *testInput) { in.Tags = []string{} }, []string{"Tags is required"}},
+		{"nil map", func(in *testInput) { in.Input = nil }, []string{"Input is required"}},
+		{"empty map", func(in *testInput) { in.Input = map[string]string{} }, []string{"Input is required"}},
+		{"below min", func(in *testInput) { in.Timeout = Int(0) }, []string{"Timeout must be at least 1"}},
+		{"float below min", func(in *testInput) { ratio := -0.1; in.Ratio = &ratio }, []string{"Ratio must be at least 0"}},
+		{"at min", func(in *testInput) { in.Timeout = Int(1) }, nil},
+		{"nested", func(in *testInput) { in.Nested = &nestedInput{TTL: Int(0)} }, []string{"Nested.TTL must be at least 1", "Nested.Name is required"}},
+		{"several", func(in *testInput) { in.Id, in.Timeout = nil, Int(-1) }, []string{"Id is required", "Timeout must be at least 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := validInput()
+			tt.modify(&in)
+			err := ApplyDefaults(&in)
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("ApplyDefaults: %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("err = %v, want a ValidationError", err)
+			}
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("err does not match ErrInvalidInput")
+			}
+			if validationErr.Type != "testInput" {
+				t.Errorf("Type = %q, want testInput", validationErr.Type)
+			}
+			var got []string
+			for _, field := range validationErr.Fields {
+				got = append(got, field.Error())
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.fields, "; ") {
+				t.Errorf("fields = %q, want %q", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsBadTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{"bad default", &struct {
+			N *int `default:"three"`
+		}{}, "field N: bad default tag"},
+		{"unsupported default", &struct {
+			S *[]string `default:"a"`
+		}{}, "field S: bad default tag"},
+		{"bad min", &struct {
+			N *int `min:"one"`
+		}{N: Int(1)}, "field N: bad min tag"},
+		{"min on string", &struct {
+			S *string `min:"1"`
+		}{S: String("a")}, "field S: min tag on string"},
+		{"not a pointer", struct{}{}, "is not a pointer to a struct"},
+		{"nil pointer", (*testInput)(nil), "is not a pointer to a struct"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ApplyDefaults(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.want)
+			}
+			if errors.Is(err, ErrInvalidInput) {
+				t.Errorf("a bad tag must not be reported as invalid input")
+			}
+		})
+	}
+}
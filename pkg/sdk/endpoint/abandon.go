@@ -0,0 +1,35 @@
+package endpoint
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// abandoned cancels the job a sync call was waiting for when the caller's
+// context ended first and Option.CancelOnAbandon is set. err is returned
+// as is otherwise, or wrapped in an *AbandonedError reporting the cancel.
+func (ep *Endpoint) abandoned(parent context.Context, id *string, status *JobStatus, err error) error {
+	if !ep.cancelOnAbandon || parent.Err() == nil || id == nil {
+		return err
+	}
+	if isTerminal(status) {
+		return err
+	}
+
+	// keep the caller's values, such as the trace, but not its cancellation
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), ep.cancelTimeout)
+	defer cancel()
+	timeout := int((ep.cancelTimeout + time.Second - 1) / time.Second)
+	output, cancelErr := ep.CancelWithContext(ctx, &CancelInput{Id: id, RequestTimeout: &timeout})
+
+	if ep.logger != nil {
+		attrs := []slog.Attr{slog.String("job_id", *id), slog.String("reason", err.Error())}
+		if cancelErr != nil {
+			ep.logger.LogAttrs(ctx, slog.LevelWarn, "runpod abandoned job cancel failed", append(attrs, slog.String("error", cancelErr.Error()))...)
+		} else {
+			ep.logger.LogAttrs(ctx, slog.LevelInfo, "runpod abandoned job cancelled", attrs...)
+		}
+	}
+	return &AbandonedError{JobId: *id, Err: err, Cancel: output, CancelErr: cancelErr}
+}
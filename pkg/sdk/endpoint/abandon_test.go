@@ -0,0 +1,107 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+func cancelOnAbandon(opt *endpoint.Option) {
+	opt.CancelOnAbandon = sdk.Bool(true)
+}
+
+func TestRunSyncCancelOnAbandon(t *testing.T) {
+	srv, ep := newTestEndpoint(t, cancelOnAbandon)
+	srv.Script = script(endpointtest.Script{Hang: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err := ep.RunSyncWithContext(ctx, &endpoint.RunSyncInput{JobInput: jobInput()})
+
+	var abandoned *endpoint.AbandonedError
+	if !errors.As(err, &abandoned) {
+		t.Fatalf("err = %v, want an AbandonedError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want it to match context.DeadlineExceeded", err)
+	}
+	if abandoned.CancelErr != nil {
+		t.Errorf("cancel failed: %v", abandoned.CancelErr)
+	}
+	if abandoned.Cancel == nil || abandoned.Cancel.Status == nil || *abandoned.Cancel.Status != endpoint.JobStatusCancelled {
+		t.Errorf("cancel output = %+v, want a cancelled job", abandoned.Cancel)
+	}
+	if got := srv.Requests("cancel"); got != 1 {
+		t.Errorf("cancel requests = %d, want 1", got)
+	}
+	if got := srv.Requests("runsync"); got != 0 {
+		t.Errorf("runsync requests = %d, want 0", got)
+	}
+}
+
+func TestJobWaitCancelOnAbandon(t *testing.T) {
+	srv, ep := newTestEndpoint(t, cancelOnAbandon)
+	srv.Script = script(endpointtest.Script{Hang: true})
+
+	job, err := ep.Submit(context.Background(), &endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err = job.Wait(ctx)
+
+	var abandoned *endpoint.AbandonedError
+	if !errors.As(err, &abandoned) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want an AbandonedError matching context.Canceled", err)
+	}
+	if abandoned.JobId != job.Id {
+		t.Errorf("job id = %s, want %s", abandoned.JobId, job.Id)
+	}
+	if got := srv.Requests("cancel"); got != 1 {
+		t.Errorf("cancel requests = %d, want 1", got)
+	}
+}
+
+func TestAbandonWithoutCancelOnAbandon(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{Hang: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := ep.RunSyncWithContext(ctx, &endpoint.RunSyncInput{JobInput: jobInput()})
+
+	var abandoned *endpoint.AbandonedError
+	if errors.As(err, &abandoned) {
+		t.Fatalf("err = %v, want no AbandonedError", err)
+	}
+	if got := srv.Requests("cancel"); got != 0 {
+		t.Errorf("cancel requests = %d, want 0", got)
+	}
+}
+
+func TestRunSyncCancelOnAbandonCompletes(t *testing.T) {
+	srv, ep := newTestEndpoint(t, cancelOnAbandon)
+	srv.Script = script(endpointtest.Script{ExecutionTime: 20 * time.Millisecond, Output: "done"})
+
+	start := time.Now()
+	output, err := ep.RunSync(&endpoint.RunSyncInput{JobInput: jobInput(), Timeout: sdk.Int(5)})
+	if err != nil {
+		t.Fatalf("RunSync: %v", err)
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusCompleted {
+		t.Errorf("status = %v, want %s", output.Status, endpoint.JobStatusCompleted)
+	}
+	// the first status poll must not wait for the default one second interval
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("RunSync took %s for a 20ms job", elapsed)
+	}
+	if got := srv.Requests("cancel"); got != 0 {
+		t.Errorf("cancel requests = %d, want 0", got)
+	}
+}
@@ -0,0 +1,209 @@
+package endpoint
+
+import (
+	"context"
+	"sync"
+)
+
+type BatchOption struct {
+	// Concurrency is the maximum number of submissions in flight.
+	Concurrency *int `default:"8" min:"1"`
+	// RequestTimeout is the timeout in seconds of each submission. Defaults to
+	// the endpoint's request timeout.
+	RequestTimeout *int `min:"1"`
+	// Wait makes every item also wait for its job to reach a terminal status.
+	Wait *bool `default:"false"`
+	// WaitConcurrency is the maximum number of jobs awaited at once. Defaults
+	// to Concurrency.
+	WaitConcurrency *int `min:"1"`
+	// PollStrategy paces the status polls of awaited jobs. Defaults to
+	// Option.PollStrategy.
+	PollStrategy PollStrategy
+	// OnProgress is called each time an item is submitted or finished. Calls
+	// are serialized.
+	OnProgress func(BatchProgress)
+}
+
+type BatchProgress struct {
+	// Total is the number of items, or 0 when reading from a channel.
+	Total     int
+	Submitted int
+	// Finished counts items whose result is available, failed ones included.
+	Finished int
+	// Failed counts items that returned an error or, with Wait, whose job did
+	// not complete successfully.
+	Failed int
+}
+
+type BatchResult struct {
+	// Index is the position of the item in the input slice or channel.
+	Index int
+	Input *JobInput
+	Run   *RunOutput
+	Job   *Job
+	// Status is the terminal status of the job, set when BatchOption.Wait is.
+	Status *StatusOutput
+	Err    error
+}
+
+// RunMany submits every input with bounded concurrency and returns the
+// results in input order. Per item failures are reported on the results; the
+// returned error is set when option is invalid, or when ctx ended before
+// every item was processed, in which case the remaining items carry ctx's
+// error.
+func (ep *Endpoint) RunMany(ctx context.Context, inputs []*JobInput, option *BatchOption) ([]BatchResult, error) {
+	option, err := prepareBatch(ep, option)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	feed := make(chan *JobInput)
+	go func() {
+		defer close(feed)
+		for _, input := range inputs {
+			select {
+			case feed <- input:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make([]BatchResult, len(inputs))
+	seen := make([]bool, len(inputs))
+	for result := range ep.runMany(ctx, feed, option, len(inputs)) {
+		results[result.Index] = result
+		seen[result.Index] = true
+	}
+
+	for i := range results {
+		if !seen[i] {
+			err = ctx.Err()
+			results[i] = BatchResult{Index: i, Input: inputs[i], Err: err}
+		}
+	}
+	return results, err
+}
+
+// RunManyChan submits inputs read from a channel with bounded concurrency.
+// Results are sent as they finish, not in input order, and the returned
+// channel is closed once inputs is closed and every item finished, or ctx
+// ended. The caller must drain the returned channel. An error is only
+// returned for an invalid option, before anything is submitted.
+func (ep *Endpoint) RunManyChan(ctx context.Context, inputs <-chan *JobInput, option *BatchOption) (<-chan BatchResult, error) {
+	option, err := prepareBatch(ep, option)
+	if err != nil {
+		return nil, err
+	}
+	return ep.runMany(ctx, inputs, option, 0), nil
+}
+
+// prepareBatch applies the defaults of a BatchOption, which may be nil.
+func prepareBatch(ep *Endpoint, option *BatchOption) (*BatchOption, error) {
+	if option == nil {
+		option = &BatchOption{}
+	}
+	option, err := prepare(ep, option)
+	if err != nil {
+		return nil, err
+	}
+	if option.WaitConcurrency == nil {
+		option.WaitConcurrency = option.Concurrency
+	}
+	return option, nil
+}
+
+// runMany processes inputs with an option prepared by prepareBatch.
+func (ep *Endpoint) runMany(ctx context.Context, inputs <-chan *JobInput, option *BatchOption, total int) <-chan BatchResult {
+	concurrency, waitConcurrency := *option.Concurrency, *option.WaitConcurrency
+	wait := *option.Wait
+
+	results := make(chan BatchResult)
+	progress := &batchProgress{progress: BatchProgress{Total: total}, onProgress: option.OnProgress}
+
+	go func() {
+		defer close(results)
+		var wg sync.WaitGroup
+		submitSem := make(chan struct{}, concurrency)
+		waitSem := make(chan struct{}, waitConcurrency)
+
+		for index := 0; ; index++ {
+			var input *JobInput
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			case in, ok := <-inputs:
+				if !ok {
+					wg.Wait()
+					return
+				}
+				input = in
+			}
+			select {
+			case submitSem <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+
+			wg.Add(1)
+			go func(index int, input *JobInput) {
+				defer wg.Done()
+				result := BatchResult{Index: index, Input: input}
+				result.Job, result.Run, result.Err = ep.submit(ctx, &RunInput{JobInput: input, RequestTimeout: option.RequestTimeout})
+				<-submitSem
+				progress.submitted(result.Err == nil)
+
+				if result.Err == nil && wait {
+					select {
+					case waitSem <- struct{}{}:
+						result.Status, result.Err = result.Job.WaitWithStrategy(ctx, option.PollStrategy)
+						<-waitSem
+					case <-ctx.Done():
+						result.Err = ctx.Err()
+					}
+				}
+
+				failed := result.Err != nil
+				if wait && result.Status != nil && result.Status.Status != nil {
+					failed = failed || !result.Status.Status.IsSuccess()
+				}
+				progress.finished(failed)
+				results <- result
+			}(index, input)
+		}
+	}()
+	return results
+}
+
+type batchProgress struct {
+	mu         sync.Mutex
+	progress   BatchProgress
+	onProgress func(BatchProgress)
+}
+
+func (p *batchProgress) submitted(ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ok {
+		p.progress.Submitted++
+	}
+	if p.onProgress != nil {
+		p.onProgress(p.progress)
+	}
+}
+
+func (p *batchProgress) finished(failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Finished++
+	if failed {
+		p.progress.Failed++
+	}
+	if p.onProgress != nil {
+		p.onProgress(p.progress)
+	}
+}
@@ -0,0 +1,222 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+func TestRunManyInvalidOption(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+
+	for _, option := range []*endpoint.BatchOption{
+		{Concurrency: sdk.Int(0)},
+		{WaitConcurrency: sdk.Int(-1)},
+		{RequestTimeout: sdk.Int(0)},
+	} {
+		_, err := ep.RunMany(context.Background(), []*endpoint.JobInput{jobInput()}, option)
+		if !errors.Is(err, sdk.ErrInvalidInput) {
+			t.Errorf("RunMany err = %v, want ErrInvalidInput", err)
+		}
+		_, err = ep.RunManyChan(context.Background(), make(chan *endpoint.JobInput), option)
+		if !errors.Is(err, sdk.ErrInvalidInput) {
+			t.Errorf("RunManyChan err = %v, want ErrInvalidInput", err)
+		}
+	}
+	if got := srv.Requests("run"); got != 0 {
+		t.Errorf("run requests = %d, want 0", got)
+	}
+}
+
+func TestRunManyDefaultConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	track := func(next endpoint.Doer) endpoint.Doer {
+		return endpoint.DoerFunc(func(req *endpoint.Request) (*endpoint.Response, error) {
+			mu.Lock()
+			inFlight++
+			if inFlight > peak {
+				peak = inFlight
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+			time.Sleep(10 * time.Millisecond)
+			return next.Do(req)
+		})
+	}
+	srv, ep := newTestEndpoint(t, func(opt *endpoint.Option) {
+		opt.Middleware = []endpoint.Middleware{track}
+	})
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+
+	inputs := make([]*endpoint.JobInput, 20)
+	for i := range inputs {
+		inputs[i] = jobInput()
+	}
+	results, err := ep.RunMany(context.Background(), inputs, nil)
+	if err != nil {
+		t.Fatalf("RunMany: %v", err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatalf("item %d: %v", result.Index, result.Err)
+		}
+	}
+	if peak > 8 {
+		t.Errorf("peak concurrency = %d, want at most the default of 8", peak)
+	}
+	if got := srv.Requests("run"); got != len(inputs) {
+		t.Errorf("run requests = %d, want %d", got, len(inputs))
+	}
+}
+
+// indexedInputs returns n inputs carrying their index, for scripts that
+// depend on the item.
+func indexedInputs(n int) []*endpoint.JobInput {
+	inputs := make([]*endpoint.JobInput, n)
+	for i := range inputs {
+		inputs[i] = &endpoint.JobInput{Input: map[string]interface{}{"index": i}}
+	}
+	return inputs
+}
+
+// inputIndex reads the index of an input, as sent or as decoded by the
+// test server.
+func inputIndex(input *endpoint.JobInput) int {
+	switch index := input.Input["index"].(type) {
+	case int:
+		return index
+	case float64:
+		return int(index)
+	}
+	return -1
+}
+
+func TestRunManyWait(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	// later items finish first, and the last one fails
+	const n = 4
+	srv.Script = func(input *endpoint.JobInput) endpointtest.Script {
+		index := inputIndex(input)
+		s := endpointtest.Script{ExecutionTime: time.Duration(n-index) * 50 * time.Millisecond, Output: index}
+		if index == n-1 {
+			s.Error = "out of memory"
+		}
+		return s
+	}
+
+	var mu sync.Mutex
+	var progress []endpoint.BatchProgress
+	results, err := ep.RunMany(context.Background(), indexedInputs(n), &endpoint.BatchOption{
+		Wait: sdk.Bool(true),
+		OnProgress: func(p endpoint.BatchProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress = append(progress, p)
+		},
+	})
+	if err != nil {
+		t.Fatalf("RunMany: %v", err)
+	}
+
+	for i, result := range results {
+		if result.Index != i || inputIndex(result.Input) != i {
+			t.Errorf("result %d has index %d and input %v", i, result.Index, result.Input.Input)
+		}
+		if result.Err != nil {
+			t.Errorf("item %d: %v", i, result.Err)
+			continue
+		}
+		want := endpoint.JobStatusCompleted
+		if i == n-1 {
+			want = endpoint.JobStatusFailed
+		}
+		if result.Status == nil || *result.Status.Status != want {
+			t.Errorf("item %d status = %+v, want %s", i, result.Status, want)
+		}
+	}
+
+	// one call per submission and one per finished item
+	if len(progress) != 2*n {
+		t.Fatalf("OnProgress called %d times, want %d", len(progress), 2*n)
+	}
+	last := progress[len(progress)-1]
+	if last != (endpoint.BatchProgress{Total: n, Submitted: n, Finished: n, Failed: 1}) {
+		t.Errorf("last progress = %+v", last)
+	}
+	for i := 1; i < len(progress); i++ {
+		if progress[i].Submitted < progress[i-1].Submitted || progress[i].Finished < progress[i-1].Finished {
+			t.Errorf("progress went backwards: %+v then %+v", progress[i-1], progress[i])
+		}
+	}
+}
+
+func TestRunManyNoWait(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+
+	results, err := ep.RunMany(context.Background(), indexedInputs(3), nil)
+	if err != nil {
+		t.Fatalf("RunMany: %v", err)
+	}
+	for i, result := range results {
+		if result.Err != nil || result.Job == nil || result.Run == nil {
+			t.Errorf("item %d = %+v, want a submitted job", i, result)
+		}
+		if result.Status != nil {
+			t.Errorf("item %d status = %+v, want nil without Wait", i, result.Status)
+		}
+	}
+	if got := srv.Requests("status-sync") + srv.Requests("status"); got != 0 {
+		t.Errorf("status requests = %d, want 0 without Wait", got)
+	}
+}
+
+func TestRunManyContextCancelled(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+
+	const n, submitted = 10, 3
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, err := ep.RunMany(ctx, indexedInputs(n), &endpoint.BatchOption{
+		Concurrency: sdk.Int(1),
+		OnProgress: func(p endpoint.BatchProgress) {
+			if p.Submitted == submitted {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(results) != n {
+		t.Fatalf("got %d results, want %d", len(results), n)
+	}
+	for i, result := range results {
+		if result.Index != i || result.Input == nil {
+			t.Errorf("result %d = %+v, want its index and input", i, result)
+		}
+		if i < submitted {
+			if result.Err != nil || result.Job == nil {
+				t.Errorf("item %d = %+v, want a submitted job", i, result)
+			}
+		} else if result.Err == nil {
+			t.Errorf("item %d has no error after ctx was cancelled", i)
+		}
+	}
+	// the item read before the cancellation may still be sent
+	if got := srv.Requests("run"); got > submitted+1 {
+		t.Errorf("run requests = %d, want at most %d", got, submitted+1)
+	}
+}
@@ -0,0 +1,47 @@
+package endpoint
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk/config"
+)
+
+// defaultClient is only used by endpoints that were not built with New.
+var defaultClient = newHTTPClient(nil)
+
+// newHTTPClient returns a keep-alive client for a single endpoint. Per-request
+// timeouts are applied through the request context, so the client itself has
+// no Timeout and can be shared by long polls and short calls alike.
+func newHTTPClient(transport http.RoundTripper) *http.Client {
+	if transport == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.MaxIdleConns = 100
+		t.MaxIdleConnsPerHost = 100
+		t.IdleConnTimeout = 90 * time.Second
+		transport = t
+	}
+	return &http.Client{Transport: transport}
+}
+
+// resolveHTTPClient picks the client for an endpoint, the option taking
+// precedence over the config.
+func resolveHTTPClient(cf *config.Config, input *Option) *http.Client {
+	switch {
+	case input.HTTPClient != nil:
+		return input.HTTPClient
+	case input.Transport != nil:
+		return newHTTPClient(input.Transport)
+	case cf.HTTPClient != nil:
+		return cf.HTTPClient
+	default:
+		return newHTTPClient(cf.Transport)
+	}
+}
+
+func (ep *Endpoint) httpClient() *http.Client {
+	if ep.client == nil {
+		return defaultClient
+	}
+	return ep.client
+}
@@ -0,0 +1,106 @@
+package endpoint_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/runpod/go-sdk/pkg/sdk/config"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+// countingTransport counts the requests it sends.
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHTTPClientPrecedence(t *testing.T) {
+	const (
+		configTransport = iota
+		configClient
+		optionTransport
+		optionClient
+	)
+	names := []string{"config transport", "config client", "option transport", "option client"}
+
+	tests := []struct {
+		name string
+		set  []int
+		want int
+	}{
+		{"config transport", []int{configTransport}, configTransport},
+		{"config client over config transport", []int{configTransport, configClient}, configClient},
+		{"option transport over config", []int{configTransport, configClient, optionTransport}, optionTransport},
+		{"option client over everything", []int{configTransport, configClient, optionTransport, optionClient}, optionClient},
+		{"option client over option transport", []int{optionTransport, optionClient}, optionClient},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv := endpointtest.NewServer()
+			defer srv.Close()
+
+			transports := make([]*countingTransport, len(names))
+			for i := range transports {
+				transports[i] = &countingTransport{}
+			}
+			cfg, opt := srv.Config(), srv.Option()
+			for _, source := range tt.set {
+				switch source {
+				case configTransport:
+					cfg.Transport = transports[source]
+				case configClient:
+					cfg.HTTPClient = &http.Client{Transport: transports[source]}
+				case optionTransport:
+					opt.Transport = transports[source]
+				case optionClient:
+					opt.HTTPClient = &http.Client{Transport: transports[source]}
+				}
+			}
+			ep, err := endpoint.New(cfg, opt)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if _, err := ep.HealthWithContext(context.Background(), &endpoint.HealthInput{}); err != nil {
+				t.Fatalf("Health: %v", err)
+			}
+
+			for i, transport := range transports {
+				want := int32(0)
+				if i == tt.want {
+					want = 1
+				}
+				if got := transport.requests.Load(); got != want {
+					t.Errorf("%s sent %d requests, want %d", names[i], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPClientSharedByConfig(t *testing.T) {
+	srv := endpointtest.NewServer()
+	defer srv.Close()
+
+	transport := &countingTransport{}
+	cfg := &config.Config{ApiKey: srv.Config().ApiKey, HTTPClient: &http.Client{Transport: transport}}
+	for i := 0; i < 2; i++ {
+		ep, err := endpoint.New(cfg, srv.Option())
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		if _, err := ep.HealthWithContext(context.Background(), &endpoint.HealthInput{}); err != nil {
+			t.Fatalf("Health: %v", err)
+		}
+	}
+	if got := transport.requests.Load(); got != 2 {
+		t.Errorf("config client sent %d requests, want 2", got)
+	}
+}
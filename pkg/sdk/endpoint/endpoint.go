@@ -1,75 +1,114 @@
 package endpoint
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/runpod/go-sdk/pkg/sdk"
 	"github.com/runpod/go-sdk/pkg/sdk/config"
 )
 
-func isCompleted(status string) bool {
-	return status == "COMPLETED" || status == "FAILED" || status == "CANCELLED" || status == "TIMED_OUT"
-}
-
-var slsEndpointUrl = "https://api.runpod.ai/v2"
-
 func New(cf *config.Config, input *Option) (*Endpoint, error) {
-	if cf.ApiKey == nil {
-		return nil, fmt.Errorf("api key is required")
+	// defaults are applied to copies, never to the caller's values
+	cfg, option := *cf, *input
+	if option.EndpointUrl == nil {
+		option.EndpointUrl = cfg.BaseURL
 	}
-	if input.EndpointId == nil {
-		return nil, fmt.Errorf("endpoint id is required")
-	}
-	if input.EndpointUrl != nil {
-		return &Endpoint{apiKey: cf.ApiKey, EndpointId: input.EndpointId, EndpointUrl: input.EndpointUrl}, nil
+	if err := errors.Join(sdk.ApplyDefaults(&cfg), sdk.ApplyDefaults(&option)); err != nil {
+		return nil, err
 	}
-	return &Endpoint{apiKey: cf.ApiKey, EndpointId: input.EndpointId, EndpointUrl: &slsEndpointUrl}, nil
+
+	client := resolveHTTPClient(&cfg, &option)
+	retry := option.RetryPolicy
+	if retry == nil {
+		retry = DefaultRetryPolicy()
+	}
+	limiter := option.RateLimiter
+	if limiter == nil {
+		limiter = cfg.RateLimiter
+	}
+	logger := option.Logger
+	if logger == nil {
+		logger = cfg.Logger
+	}
+	endpointUrl := strings.TrimSuffix(*option.EndpointUrl, "/")
+	return &Endpoint{
+		apiKey:                cfg.ApiKey,
+		client:                client,
+		doer:                  newDoer(client, retry, limiter, logger, option.Middleware, option.AttemptMiddleware),
+		hooks:                 option.OperationHooks,
+		logger:                logger,
+		requestTimeoutDefault: cfg.RequestTimeout,
+		syncTimeoutDefault:    cfg.SyncTimeout,
+		defaultPollStrategy:   option.PollStrategy,
+		cancelOnAbandon:       *option.CancelOnAbandon,
+		cancelTimeout:         time.Duration(*option.CancelTimeout) * time.Second,
+		EndpointId:            option.EndpointId,
+		EndpointUrl:           &endpointUrl,
+	}, nil
 }
 
 func (ep *Endpoint) Run(input *RunInput) (*RunOutput, error) {
-	var timeout int
-	if input.RequestTimeout != nil {
-		timeout = *input.RequestTimeout
-	} else {
-		timeout = 3
+	return ep.RunWithContext(context.Background(), input)
+}
+
+// RunWithContext is like Run but aborts the request as soon as ctx is done.
+func (ep *Endpoint) RunWithContext(ctx context.Context, input *RunInput) (*RunOutput, error) {
+	ctx, end := ep.startOperation(ctx, OperationRun, nil)
+	output, err := ep.run(ctx, input)
+	end(output, err)
+	return output, err
+}
+
+func (ep *Endpoint) run(ctx context.Context, input *RunInput) (*RunOutput, error) {
+	input, err := prepare(ep, input)
+	if err != nil {
+		return nil, err
 	}
+	timeout := *input.RequestTimeout
 
 	reqBody, err := json.Marshal(input.JobInput)
 	if err != nil {
-		return nil, fmt.Errorf("json marshal error: %s", err)
+		return nil, fmt.Errorf("json marshal error: %w", err)
 	}
 
 	url := *ep.EndpointUrl + "/" + *ep.EndpointId + "/run"
 
-	respBody, err := getApiResponse(apiRequestInput{method: "POST", url: &url, reqBody: reqBody, token: ep.apiKey, timeout: &timeout})
-	if err != nil {
-		return nil, err
-	}
 	var result RunOutput
-	err = json.Unmarshal(respBody, &result)
+	_, err = ep.getApiResponse(ctx, apiRequestInput{op: OperationRun, method: "POST", url: &url, reqBody: reqBody, token: ep.apiKey, timeout: &timeout, result: &result})
 	if err != nil {
-		return nil, fmt.Errorf("json decoder error: %s", err)
+		return nil, err
 	}
 	return &result, nil
 }
 
 func (ep *Endpoint) RunSync(input *RunSyncInput) (*RunSyncOutput, error) {
+	return ep.RunSyncWithContext(context.Background(), input)
+}
 
-	wait := 90 * 1000
-	var timeout, reqTimeout int
+// RunSyncWithContext is like RunSync but stops submitting and polling as soon
+// as ctx is done. The input Timeout still bounds the whole call.
+func (ep *Endpoint) RunSyncWithContext(ctx context.Context, input *RunSyncInput) (*RunSyncOutput, error) {
+	ctx, end := ep.startOperation(ctx, OperationRunSync, nil)
+	output, err := ep.runSync(ctx, input)
+	end(output, err)
+	return output, err
+}
 
-	if input.Timeout != nil {
-		timeout = *input.Timeout
-	} else {
-		timeout = 90
+func (ep *Endpoint) runSync(parent context.Context, input *RunSyncInput) (*RunSyncOutput, error) {
+	input, err := prepare(ep, input)
+	if err != nil {
+		return nil, err
 	}
+	wait := 90 * 1000
+	timeout := *input.Timeout
+	var reqTimeout int
 
 	if timeout >= 90 {
 		reqTimeout = 90 + 2
@@ -78,66 +117,56 @@ func (ep *Endpoint) RunSync(input *RunSyncInput) (*RunSyncOutput, error) {
 		reqTimeout = timeout + 2
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout+3)*time.Second)
+	ctx, cancel := context.WithTimeout(parent, time.Duration(timeout+3)*time.Second)
 	defer cancel()
 
 	url, err := getRunSyncURL(ep, wait)
 	if err != nil {
 		return nil, err
 	}
+	if ep.cancelOnAbandon {
+		// the job id must be known before blocking to cancel the job if the
+		// call is abandoned, so queue it through /run and poll right away
+		runURL := *ep.EndpointUrl + "/" + *ep.EndpointId + "/run"
+		url = &runURL
+		reqTimeout = intValue(ep.requestTimeoutDefault)
+	}
 
 	reqBody, err := json.Marshal(input.JobInput)
 	if err != nil {
-		return nil, fmt.Errorf("json marshal error: %s", err)
+		return nil, fmt.Errorf("json marshal error: %w", err)
 	}
 
 	var result RunSyncOutput
-	respBody, err := getApiResponse(apiRequestInput{method: "POST", url: url, reqBody: reqBody, token: ep.apiKey, timeout: &reqTimeout})
+	submittedAt := time.Now()
+	_, err = ep.getApiResponse(ctx, apiRequestInput{op: OperationRunSync, method: "POST", url: url, reqBody: reqBody, token: ep.apiKey, timeout: &reqTimeout, result: &result})
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(respBody, &result)
-	if err != nil {
-		return nil, fmt.Errorf("json decoder error: %s", err)
-	}
-	if result.Status != nil && (isCompleted(*result.Status)) {
+	ep.logTransition(ctx, result.Id, "", result.Status)
+	if isTerminal(result.Status) {
 		return &result, nil
 	} else if result.Error != nil {
 		return &result, nil
 	}
 
-	// request is in queue so fetch using statusSync
-	statusSyncURL, err := getStatusSyncURL(ep, result.Id, wait)
-	if err != nil {
-		return nil, err
+	// request is in queue so poll its status. /run answers right away, so
+	// the first poll is not spaced from it
+	last := submittedAt
+	if ep.cancelOnAbandon {
+		last = time.Time{}
 	}
+	err = ep.pollStatus(parent, ctx, OperationRunSync, result.Id, ep.pollStrategy(input.PollStrategy), submittedAt, last, (*StatusOutput)(&result))
+	return &result, err
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return &result, fmt.Errorf("timeout reached")
-		default:
-			respBody, err := statusSyncApiCall(ctx, ep, statusSyncURL, &reqTimeout)
-			if err != nil {
-				return &result, err
-			}
-			err = json.Unmarshal(respBody, &result)
-			if err != nil {
-				return &result, fmt.Errorf("json decoder error: %s", err)
-			}
-			err = json.Unmarshal(respBody, &result)
-			if err != nil {
-				return &result, fmt.Errorf("json decoder error: %s", err)
-			}
-			if result.Status != nil && (isCompleted(*result.Status)) {
-				return &result, nil
-			} else if result.Error != nil {
-				return &result, nil
-			}
-		}
+// ctxDoneError reports why a polling loop stopped: the caller's own context
+// error when it ended first, otherwise the SDK's timeout.
+func ctxDoneError(parent context.Context) error {
+	if err := parent.Err(); err != nil {
+		return err
 	}
-
-	// return &result, nil
+	return ErrTimeout
 }
 
 func getStatusSyncURL(ep *Endpoint, id *string, wait int) (*string, error) {
@@ -148,7 +177,7 @@ func getStatusSyncURL(ep *Endpoint, id *string, wait int) (*string, error) {
 
 	u, err := url.Parse(baseURL)
 	if err != nil {
-		return nil, fmt.Errorf("url parse error: %s", err)
+		return nil, fmt.Errorf("url parse error: %w", err)
 	}
 	u.RawQuery = queryParams.Encode()
 	url := u.String()
@@ -164,7 +193,7 @@ func getRunSyncURL(ep *Endpoint, wait int) (*string, error) {
 
 	u, err := url.Parse(baseURL)
 	if err != nil {
-		return nil, fmt.Errorf("url parse error: %s", err)
+		return nil, fmt.Errorf("url parse error: %w", err)
 	}
 	u.RawQuery = queryParams.Encode()
 	url := u.String()
@@ -173,316 +202,205 @@ func getRunSyncURL(ep *Endpoint, wait int) (*string, error) {
 }
 
 func (ep *Endpoint) StatusSync(input *StatusSyncInput) (*StatusSyncOutput, error) {
-	if input.Id == nil {
-		return nil, fmt.Errorf("job id is required")
-	}
-
-	wait := 90 * 1000
-	var timeout, reqTimeout int
-
-	if input.Timeout != nil {
-		timeout = *input.Timeout
-	} else {
-		timeout = 90
-	}
+	return ep.StatusSyncWithContext(context.Background(), input)
+}
 
-	if timeout >= 90 {
-		reqTimeout = 90 + 2
-	} else {
-		wait = timeout * 1000
-		reqTimeout = timeout + 2
-	}
+// StatusSyncWithContext is like StatusSync but stops polling as soon as ctx
+// is done.
+func (ep *Endpoint) StatusSyncWithContext(ctx context.Context, input *StatusSyncInput) (*StatusSyncOutput, error) {
+	var id *string
+	if input != nil {
+		id = input.Id
+	}
+	ctx, end := ep.startOperation(ctx, OperationStatusSync, id)
+	output, err := ep.statusSync(ctx, input)
+	end(output, err)
+	return output, err
+}
 
-	statusSyncURL, err := getStatusSyncURL(ep, input.Id, wait)
+func (ep *Endpoint) statusSync(parent context.Context, input *StatusSyncInput) (*StatusSyncOutput, error) {
+	input, err := prepare(ep, input)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout+3)*time.Second)
+	timeout := *input.Timeout
+	ctx, cancel := context.WithTimeout(parent, time.Duration(timeout+3)*time.Second)
 	defer cancel()
-	var result StatusSyncOutput
 
-	for {
-		select {
-		case <-ctx.Done():
-			return &result, fmt.Errorf("timeout reached")
-		default:
-			respBody, err := statusSyncApiCall(ctx, ep, statusSyncURL, &reqTimeout)
-			if err != nil {
-				return &result, err
-			}
-			err = json.Unmarshal(respBody, &result)
-			if err != nil {
-				return &result, fmt.Errorf("json decoder error: %s", err)
-			}
-			if result.Status != nil && (isCompleted(*result.Status)) {
-				return &result, nil
-			} else if result.Error != nil {
-				return &result, nil
-			}
-		}
-	}
+	var result StatusSyncOutput
+	err = ep.pollStatus(parent, ctx, OperationStatusSync, input.Id, ep.pollStrategy(input.PollStrategy), time.Now(), time.Time{}, (*StatusOutput)(&result))
+	return &result, err
 }
 
-func statusSyncApiCall(ctx context.Context, ep *Endpoint, url *string, reqTimeout *int) ([]byte, error) {
-	done := make(chan bool)
-	var err error
-	var respBody []byte
-	go func() {
-		respBody, err = getApiResponse(apiRequestInput{method: "POST", url: url, token: ep.apiKey, timeout: reqTimeout})
-		if err != nil {
-			done <- false
-			return
-		}
-		done <- true
-	}()
-
-	select {
-	case <-ctx.Done():
-		return respBody, fmt.Errorf("ctx timeout reached")
-	case <-done:
-		if err != nil {
-			return respBody, err
+func statusSyncApiCall(ctx context.Context, ep *Endpoint, url *string, id *string, reqTimeout *int, result interface{}) error {
+	_, err := ep.getApiResponse(ctx, apiRequestInput{op: OperationStatusSync, method: "POST", url: url, token: ep.apiKey, timeout: reqTimeout, jobId: id, result: result})
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("ctx %w", ErrTimeout)
 		}
-		return respBody, nil
+		return err
 	}
+	return nil
 }
 
 func (ep *Endpoint) Status(input *StatusInput) (*StatusOutput, error) {
-	if input.Id == nil {
-		return nil, fmt.Errorf("endpoint id is required")
-	}
-	var timeout int
-	if input.RequestTimeout != nil {
-		timeout = *input.RequestTimeout
-	} else {
-		timeout = 3
+	return ep.StatusWithContext(context.Background(), input)
+}
+
+// StatusWithContext is like Status but aborts the request as soon as ctx is
+// done.
+func (ep *Endpoint) StatusWithContext(ctx context.Context, input *StatusInput) (*StatusOutput, error) {
+	var id *string
+	if input != nil {
+		id = input.Id
+	}
+	ctx, end := ep.startOperation(ctx, OperationStatus, id)
+	output, err := ep.status(ctx, input)
+	end(output, err)
+	return output, err
+}
+
+func (ep *Endpoint) status(ctx context.Context, input *StatusInput) (*StatusOutput, error) {
+	input, err := prepare(ep, input)
+	if err != nil {
+		return nil, err
 	}
+	timeout := *input.RequestTimeout
 
 	url := *ep.EndpointUrl + "/" + *ep.EndpointId + "/status/" + *input.Id
 
 	var result StatusOutput
-	respBody, err := getApiResponse(apiRequestInput{
+	_, err = ep.getApiResponse(ctx, apiRequestInput{
+		op:      OperationStatus,
 		method:  "POST",
 		url:     &url,
 		token:   ep.apiKey,
 		timeout: &timeout,
+		jobId:   input.Id,
+		result:  &result,
 	})
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(respBody, &result)
-	if err != nil {
-		return &result, fmt.Errorf("json decoder error: %s", err)
-	}
 
 	return &result, nil
 }
 
-func getApiResponse(input apiRequestInput) ([]byte, error) {
-	var result []byte
-	req, err := http.NewRequest(input.method, *input.url, bytes.NewBuffer(input.reqBody))
-	if err != nil {
-		return result, fmt.Errorf("http request create error: %s", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+*input.token)
-
-	client := &http.Client{Timeout: time.Second * time.Duration(*input.timeout)}
-	resp, err := client.Do(req)
-	if err != nil {
-		return result, fmt.Errorf("sls request error: %s", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return result, fmt.Errorf("response status %s", resp.Status)
-	}
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return result, fmt.Errorf("io read error: %s", err)
-	}
+func (ep *Endpoint) Health(input *HealthInput) (*HealthOutput, error) {
+	return ep.HealthWithContext(context.Background(), input)
+}
 
-	return respBody, nil
+// HealthWithContext is like Health but aborts the request as soon as ctx is
+// done.
+func (ep *Endpoint) HealthWithContext(ctx context.Context, input *HealthInput) (*HealthOutput, error) {
+	ctx, end := ep.startOperation(ctx, OperationHealth, nil)
+	output, err := ep.health(ctx, input)
+	end(output, err)
+	return output, err
 }
 
-func (ep *Endpoint) Health(input *HealthInput) (*HealthOutput, error) {
-	var timeout int
-	if input.RequestTimeout != nil {
-		timeout = *input.RequestTimeout
-	} else {
-		timeout = 3
+func (ep *Endpoint) health(ctx context.Context, input *HealthInput) (*HealthOutput, error) {
+	input, err := prepare(ep, input)
+	if err != nil {
+		return nil, err
 	}
+	timeout := *input.RequestTimeout
 
 	url := *ep.EndpointUrl + "/" + *ep.EndpointId + "/health"
 
 	var result HealthOutput
-	respBody, err := getApiResponse(apiRequestInput{
+	_, err = ep.getApiResponse(ctx, apiRequestInput{
+		op:      OperationHealth,
 		method:  "GET",
 		url:     &url,
 		token:   ep.apiKey,
 		timeout: &timeout,
+		result:  &result,
 	})
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(respBody, &result)
-	if err != nil {
-		return &result, fmt.Errorf("json decoder error: %s", err)
-	}
 
 	return &result, nil
 }
 
 func (ep *Endpoint) PurgeQueue(input *PurgeQueueInput) (*PurgeQueueOutput, error) {
-	var timeout int
-	if input.RequestTimeout != nil {
-		timeout = *input.RequestTimeout
-	} else {
-		timeout = 3
+	return ep.PurgeQueueWithContext(context.Background(), input)
+}
+
+// PurgeQueueWithContext is like PurgeQueue but aborts the request as soon as
+// ctx is done.
+func (ep *Endpoint) PurgeQueueWithContext(ctx context.Context, input *PurgeQueueInput) (*PurgeQueueOutput, error) {
+	ctx, end := ep.startOperation(ctx, OperationPurgeQueue, nil)
+	output, err := ep.purgeQueue(ctx, input)
+	end(output, err)
+	return output, err
+}
+
+func (ep *Endpoint) purgeQueue(ctx context.Context, input *PurgeQueueInput) (*PurgeQueueOutput, error) {
+	input, err := prepare(ep, input)
+	if err != nil {
+		return nil, err
 	}
+	timeout := *input.RequestTimeout
 
 	url := *ep.EndpointUrl + "/" + *ep.EndpointId + "/purge-queue"
 
 	var result PurgeQueueOutput
-	respBody, err := getApiResponse(apiRequestInput{
+	_, err = ep.getApiResponse(ctx, apiRequestInput{
+		op:      OperationPurgeQueue,
 		method:  "POST",
 		url:     &url,
 		token:   ep.apiKey,
 		timeout: &timeout,
+		result:  &result,
 	})
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(respBody, &result)
-	if err != nil {
-		return &result, fmt.Errorf("json decoder error: %s", err)
-	}
 	return &result, nil
 }
 
 func (ep *Endpoint) Cancel(input *CancelInput) (*CancelOutput, error) {
-	if input.Id == nil {
-		return nil, fmt.Errorf("job id is required")
-	}
+	return ep.CancelWithContext(context.Background(), input)
+}
 
-	var timeout int
-	if input.RequestTimeout != nil {
-		timeout = *input.RequestTimeout
-	} else {
-		timeout = 3
+// CancelWithContext is like Cancel but aborts the request as soon as ctx is
+// done.
+func (ep *Endpoint) CancelWithContext(ctx context.Context, input *CancelInput) (*CancelOutput, error) {
+	var id *string
+	if input != nil {
+		id = input.Id
+	}
+	ctx, end := ep.startOperation(ctx, OperationCancel, id)
+	output, err := ep.cancel(ctx, input)
+	end(output, err)
+	return output, err
+}
+
+func (ep *Endpoint) cancel(ctx context.Context, input *CancelInput) (*CancelOutput, error) {
+	input, err := prepare(ep, input)
+	if err != nil {
+		return nil, err
 	}
 
+	timeout := *input.RequestTimeout
+
 	url := *ep.EndpointUrl + "/" + *ep.EndpointId + "/cancel/" + *input.Id
 
 	var result CancelOutput
-	respBody, err := getApiResponse(apiRequestInput{
+	_, err = ep.getApiResponse(ctx, apiRequestInput{
+		op:      OperationCancel,
 		method:  "POST",
 		url:     &url,
 		token:   ep.apiKey,
 		timeout: &timeout,
+		jobId:   input.Id,
+		result:  &result,
 	})
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(respBody, &result)
-	if err != nil {
-		return &result, fmt.Errorf("json decoder error: %s", err)
-	}
 	return &result, nil
 }
-
-func (ep *Endpoint) Stream(input *StreamInput, outputChan chan<- StreamResult) error {
-	if input.Id == nil {
-		return fmt.Errorf("job id is required")
-	}
-
-	wait := 90 * 1000
-	var timeout, reqTimeout int
-
-	if input.Timeout != nil {
-		timeout = *input.Timeout
-	} else {
-		timeout = 90
-	}
-
-	if timeout >= 90 {
-		reqTimeout = 90 + 2
-	} else {
-		wait = timeout * 1000
-		reqTimeout = timeout + 2
-	}
-
-	queryParams := url.Values{}
-	queryParams.Add("wait", strconv.Itoa(wait))
-
-	baseURL := *ep.EndpointUrl + "/" + *ep.EndpointId + "/stream/" + *input.Id
-
-	u, err := url.Parse(baseURL)
-	if err != nil {
-		return fmt.Errorf("url parse error: %s", err)
-	}
-	u.RawQuery = queryParams.Encode()
-	url := u.String()
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout+3)*time.Second)
-	defer cancel()
-
-	defer func() {
-		if outputChan != nil {
-			close(outputChan)
-		}
-	}()
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("ctx timeout reached")
-		default:
-			result, err := streamApiCall(ctx, ep, &url, &reqTimeout)
-			if err != nil {
-				return err
-			}
-			for _, streamResult := range result.Stream {
-				outputChan <- streamResult
-			}
-			if result.Status != nil && (isCompleted(*result.Status)) {
-				return nil
-			}
-		}
-	}
-
-	return nil
-
-}
-
-func streamApiCall(ctx context.Context, ep *Endpoint, url *string, reqTimeout *int) (StreamOutput, error) {
-	done := make(chan bool)
-	var err error
-	var result StreamOutput
-	var respBody []byte
-	go func() {
-		respBody, err = getApiResponse(apiRequestInput{method: "POST", url: url, token: ep.apiKey, timeout: reqTimeout})
-		if err != nil {
-			done <- false
-			return
-		}
-		err = json.Unmarshal(respBody, &result)
-		if err != nil {
-			done <- false
-			return
-		}
-		done <- true
-	}()
-
-	select {
-	case <-ctx.Done():
-		return result, fmt.Errorf("ctx timeout reached")
-	case <-done:
-		if err != nil {
-			return result, err
-		}
-		return result, nil
-	}
-}
@@ -0,0 +1,333 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+func newTestEndpoint(t *testing.T, option func(*endpoint.Option)) (*endpointtest.Server, *endpoint.Endpoint) {
+	t.Helper()
+	srv := endpointtest.NewServer()
+	t.Cleanup(srv.Close)
+	opt := srv.Option()
+	if option != nil {
+		option(opt)
+	}
+	ep, err := endpoint.New(srv.Config(), opt)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return srv, ep
+}
+
+func script(s endpointtest.Script) func(*endpoint.JobInput) endpointtest.Script {
+	return func(*endpoint.JobInput) endpointtest.Script { return s }
+}
+
+func jobInput() *endpoint.JobInput {
+	return &endpoint.JobInput{Input: map[string]interface{}{"prompt": "hello"}}
+}
+
+func TestRun(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+
+	output, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if output.Id == nil || *output.Id == "" {
+		t.Fatalf("Run returned no job id")
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusInQueue {
+		t.Errorf("status = %v, want %s", output.Status, endpoint.JobStatusInQueue)
+	}
+	if got := srv.Requests("run"); got != 1 {
+		t.Errorf("run requests = %d, want 1", got)
+	}
+}
+
+func TestRunSync(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{QueueDelay: 20 * time.Millisecond, ExecutionTime: 20 * time.Millisecond, Output: "done"})
+
+	output, err := ep.RunSync(&endpoint.RunSyncInput{JobInput: jobInput(), Timeout: sdk.Int(5)})
+	if err != nil {
+		t.Fatalf("RunSync: %v", err)
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusCompleted {
+		t.Fatalf("status = %v, want %s", output.Status, endpoint.JobStatusCompleted)
+	}
+	if output.Output == nil || *output.Output != "done" {
+		t.Errorf("output = %v, want done", output.Output)
+	}
+	if output.DelayTime == nil || output.ExecutionTime == nil {
+		t.Errorf("missing timings: delay %v, execution %v", output.DelayTime, output.ExecutionTime)
+	}
+}
+
+func TestRunSyncFailed(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{Error: "out of memory"})
+
+	output, err := ep.RunSync(&endpoint.RunSyncInput{JobInput: jobInput(), Timeout: sdk.Int(5)})
+	if err != nil {
+		t.Fatalf("RunSync: %v", err)
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusFailed {
+		t.Fatalf("status = %v, want %s", output.Status, endpoint.JobStatusFailed)
+	}
+	if output.Error == nil || *output.Error != "out of memory" {
+		t.Errorf("error = %v, want out of memory", output.Error)
+	}
+}
+
+func TestStatusSync(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{ExecutionTime: 50 * time.Millisecond, Output: "done"})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	status, err := ep.Status(&endpoint.StatusInput{Id: run.Id})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Status == nil || status.Status.IsTerminal() {
+		t.Errorf("status = %v, want a running job", status.Status)
+	}
+
+	output, err := ep.StatusSync(&endpoint.StatusSyncInput{Id: run.Id, Timeout: sdk.Int(5)})
+	if err != nil {
+		t.Fatalf("StatusSync: %v", err)
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusCompleted {
+		t.Errorf("status = %v, want %s", output.Status, endpoint.JobStatusCompleted)
+	}
+}
+
+func TestStatusSyncNotFound(t *testing.T) {
+	_, ep := newTestEndpoint(t, nil)
+
+	_, err := ep.StatusSync(&endpoint.StatusSyncInput{Id: sdk.String("missing"), Timeout: sdk.Int(5)})
+	var apiErr *endpoint.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("err = %v, want a 404 APIError", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	chunks := []interface{}{"a", "b", "c"}
+	srv.Script = script(endpointtest.Script{ExecutionTime: 60 * time.Millisecond, Chunks: chunks})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	results := make(chan endpoint.StreamResult, len(chunks))
+	err = ep.Stream(&endpoint.StreamInput{Id: run.Id, Follow: sdk.Bool(true), PollWait: sdk.Int(1)}, results)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	var got []interface{}
+	for result := range results {
+		got = append(got, result["output"])
+	}
+	if len(got) != len(chunks) {
+		t.Fatalf("got %v, want %v", got, chunks)
+	}
+	for i := range chunks {
+		if got[i] != chunks[i] {
+			t.Errorf("chunk %d = %v, want %v", i, got[i], chunks[i])
+		}
+	}
+}
+
+func TestOpenStreamFinal(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{Chunks: []interface{}{"x"}})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	reader, err := ep.OpenStream(context.Background(), &endpoint.StreamInput{Id: run.Id, Follow: sdk.Bool(true), PollWait: sdk.Int(1)})
+	if err != nil {
+		t.Fatalf("OpenStream: %v", err)
+	}
+	defer reader.Close()
+	n := 0
+	for reader.Next() {
+		n++
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("read %d results, want 1", n)
+	}
+	if final := reader.Final(); final == nil || final.Status == nil || *final.Status != endpoint.JobStatusCompleted {
+		t.Errorf("final = %+v, want a completed status", final)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{Hang: true})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	output, err := ep.Cancel(&endpoint.CancelInput{Id: run.Id})
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusCancelled {
+		t.Errorf("status = %v, want %s", output.Status, endpoint.JobStatusCancelled)
+	}
+
+	status, err := ep.Status(&endpoint.StatusInput{Id: run.Id})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Status == nil || *status.Status != endpoint.JobStatusCancelled {
+		t.Errorf("status after cancel = %v, want %s", status.Status, endpoint.JobStatusCancelled)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.SetWorkers(endpoint.HealthWorkerOutput{Idle: sdk.Int(2), Running: sdk.Int(1)})
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		if _, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()}); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	health, err := ep.Health(&endpoint.HealthInput{})
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if health.Workers == nil || health.Workers.Idle == nil || *health.Workers.Idle != 2 {
+		t.Errorf("workers = %+v, want 2 idle", health.Workers)
+	}
+	if health.Jobs == nil || health.Jobs.InQueue == nil || *health.Jobs.InQueue != 3 {
+		t.Errorf("jobs = %+v, want 3 in queue", health.Jobs)
+	}
+}
+
+func TestPurgeQueue(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+
+	var ids []*string
+	for i := 0; i < 2; i++ {
+		run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		ids = append(ids, run.Id)
+	}
+	output, err := ep.PurgeQueue(&endpoint.PurgeQueueInput{})
+	if err != nil {
+		t.Fatalf("PurgeQueue: %v", err)
+	}
+	if output.Removed == nil || *output.Removed != 2 {
+		t.Errorf("removed = %v, want 2", output.Removed)
+	}
+	for _, id := range ids {
+		status, err := ep.Status(&endpoint.StatusInput{Id: id})
+		if err != nil {
+			t.Fatalf("Status: %v", err)
+		}
+		if status.Status == nil || *status.Status != endpoint.JobStatusCancelled {
+			t.Errorf("job %s status = %v, want %s", *id, status.Status, endpoint.JobStatusCancelled)
+		}
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	_, ep := newTestEndpoint(t, nil)
+
+	_, err := ep.Status(&endpoint.StatusInput{})
+	if !errors.Is(err, sdk.ErrInvalidInput) {
+		t.Fatalf("err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	srv := endpointtest.NewServer()
+	defer srv.Close()
+	cfg := srv.Config()
+	cfg.ApiKey = sdk.String("wrong")
+	ep, err := endpoint.New(cfg, srv.Option())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = ep.Health(&endpoint.HealthInput{})
+	var apiErr *endpoint.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want a 401 APIError", err)
+	}
+}
+
+func TestNilInput(t *testing.T) {
+	_, ep := newTestEndpoint(t, nil)
+
+	calls := map[string]func() error{
+		"Run":        func() error { _, err := ep.Run(nil); return err },
+		"RunSync":    func() error { _, err := ep.RunSync(nil); return err },
+		"Status":     func() error { _, err := ep.Status(nil); return err },
+		"StatusSync": func() error { _, err := ep.StatusSync(nil); return err },
+		"Cancel":     func() error { _, err := ep.Cancel(nil); return err },
+		"Health":     func() error { _, err := ep.Health(nil); return err },
+		"PurgeQueue": func() error { _, err := ep.PurgeQueue(nil); return err },
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); !errors.Is(err, sdk.ErrInvalidInput) {
+				t.Errorf("err = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestRunNotRetriedOnBadGateway(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.FailNext("run", http.StatusBadGateway, 1)
+
+	_, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	var apiErr *endpoint.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("err = %v, want a 502 APIError", err)
+	}
+	// the job may have been queued before the gateway failed, so resending
+	// could run it twice
+	if got := srv.Requests("run"); got != 1 {
+		t.Errorf("run requests = %d, want 1", got)
+	}
+}
+
+func TestRunRetriedOnTooManyRequests(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.FailNext("run", http.StatusTooManyRequests, 1)
+
+	if _, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := srv.Requests("run"); got != 2 {
+		t.Errorf("run requests = %d, want 2", got)
+	}
+}
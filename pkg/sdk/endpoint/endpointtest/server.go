@@ -0,0 +1,445 @@
+// Package endpointtest provides an in-process fake of the RunPod serverless
+// API for tests. Jobs follow a scripted lifecycle in real time, so keep the
+// scripted delays short.
+//
+//	srv := endpointtest.NewServer()
+//	defer srv.Close()
+//	srv.Script = func(input *endpoint.JobInput) endpointtest.Script {
+//		return endpointtest.Script{QueueDelay: 50 * time.Millisecond, Output: "done"}
+//	}
+//	ep, _ := endpoint.New(srv.Config(), srv.Option())
+package endpointtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/config"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+const (
+	ApiKey     = "test-api-key"
+	EndpointId = "test-endpoint"
+)
+
+// maxWait caps the wait query parameter, like the real API.
+const maxWait = 90 * time.Second
+
+// pollInterval is how often waiting requests re-check a job.
+const pollInterval = 5 * time.Millisecond
+
+// Script describes the lifecycle of a job.
+type Script struct {
+	// QueueDelay is how long the job stays IN_QUEUE.
+	QueueDelay time.Duration
+	// ExecutionTime is how long the job stays IN_PROGRESS.
+	ExecutionTime time.Duration
+	// Chunks are released evenly over ExecutionTime on the stream endpoint.
+	Chunks []interface{}
+	// Output is the job output once COMPLETED.
+	Output interface{}
+	// Error makes the job end FAILED with this error.
+	Error string
+	// TimedOut makes the job end TIMED_OUT.
+	TimedOut bool
+	// Hang keeps the job IN_PROGRESS until it is cancelled.
+	Hang bool
+}
+
+// Server is a fake RunPod serverless API.
+type Server struct {
+	*httptest.Server
+
+	// Script returns the lifecycle of a submitted job. By default jobs
+	// complete immediately and echo their input as output.
+	Script func(input *endpoint.JobInput) Script
+
+	mu       sync.Mutex
+	jobs     map[string]*job
+	nextId   int
+	workers  endpoint.HealthWorkerOutput
+	failures []failure
+	requests map[string]int
+}
+
+type job struct {
+	id        string
+	input     *endpoint.JobInput
+	script    Script
+	createdAt time.Time
+	cancelled *time.Time
+	purged    bool
+	streamed  int
+}
+
+type failure struct {
+	path   string
+	status int
+	count  int
+}
+
+func NewServer() *Server {
+	s := &Server{
+		jobs:     map[string]*job{},
+		requests: map[string]int{},
+		workers:  endpoint.HealthWorkerOutput{Idle: sdk.Int(1), Ready: sdk.Int(1)},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns a config holding the server's api key.
+func (s *Server) Config() *config.Config {
+	apiKey := ApiKey
+	return &config.Config{ApiKey: &apiKey}
+}
+
+// Option returns an endpoint option pointing at the server.
+func (s *Server) Option() *endpoint.Option {
+	endpointId, endpointUrl := EndpointId, s.URL
+	return &endpoint.Option{EndpointId: &endpointId, EndpointUrl: &endpointUrl}
+}
+
+// SetWorkers sets the worker counts reported by /health.
+func (s *Server) SetWorkers(workers endpoint.HealthWorkerOutput) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers = workers
+}
+
+// FailNext makes the next count requests whose operation matches op, e.g.
+// "run" or "status-sync", answer with the given HTTP status.
+func (s *Server) FailNext(op string, status int, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{path: op, status: status, count: count})
+}
+
+// Requests returns how many requests were received for op, e.g. "run".
+func (s *Server) Requests(op string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[op]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+ApiKey {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != EndpointId {
+		writeError(w, http.StatusNotFound, "endpoint not found")
+		return
+	}
+	op := parts[1]
+	var id string
+	if len(parts) > 2 {
+		id = parts[2]
+	}
+
+	if status, fail := s.countRequest(op); fail {
+		writeError(w, status, http.StatusText(status))
+		return
+	}
+
+	wait := maxWait
+	if v := r.URL.Query().Get("wait"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && time.Duration(ms)*time.Millisecond < maxWait {
+			wait = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	switch {
+	case op == "run" && id == "":
+		s.handleRun(w, r, 0)
+	case op == "runsync" && id == "":
+		s.handleRun(w, r, wait)
+	case op == "status" && id != "":
+		s.handleStatus(w, r, id, 0)
+	case op == "status-sync" && id != "":
+		s.handleStatus(w, r, id, wait)
+	case op == "stream" && id != "":
+		s.handleStream(w, r, id, wait)
+	case op == "cancel" && id != "":
+		s.handleCancel(w, id)
+	case op == "health" && id == "":
+		s.handleHealth(w)
+	case op == "purge-queue" && id == "":
+		s.handlePurgeQueue(w)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) countRequest(op string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[op]++
+	for i := range s.failures {
+		if s.failures[i].path == op && s.failures[i].count > 0 {
+			s.failures[i].count--
+			return s.failures[i].status, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request, wait time.Duration) {
+	var input endpoint.JobInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+
+	script := Script{Output: input.Input}
+	if s.Script != nil {
+		script = s.Script(&input)
+	}
+
+	s.mu.Lock()
+	s.nextId++
+	j := &job{id: fmt.Sprintf("test-job-%d", s.nextId), input: &input, script: script, createdAt: time.Now()}
+	s.jobs[j.id] = j
+	s.mu.Unlock()
+
+	if wait == 0 {
+		status := endpoint.JobStatusInQueue
+		writeJSON(w, endpoint.RunOutput{Id: &j.id, Status: &status})
+		return
+	}
+	s.handleStatus(w, r, j.id, wait)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request, id string, wait time.Duration) {
+	deadline := time.Now().Add(wait)
+	for {
+		output, ok := s.status(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		if output.Status.IsTerminal() || !time.Now().Before(deadline) {
+			writeJSON(w, output)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request, id string, wait time.Duration) {
+	deadline := time.Now().Add(wait)
+	for {
+		output, chunks, ok := s.stream(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		if len(chunks) > 0 || output.Status.IsTerminal() || !time.Now().Before(deadline) {
+			writeJSON(w, endpoint.StreamOutput{Status: output.Status, Error: output.Error, Stream: chunks})
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	if ok && !j.state(time.Now()).IsTerminal() {
+		now := time.Now()
+		j.cancelled = &now
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	output, _ := s.status(id)
+	writeJSON(w, endpoint.CancelOutput{Id: output.Id, Status: output.Status, DelayTime: output.DelayTime, ExecutionTime: output.ExecutionTime, Error: output.Error})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var inQueue, inProgress, completed, failed int
+	for _, j := range s.jobs {
+		if j.purged {
+			continue
+		}
+		switch j.state(now) {
+		case endpoint.JobStatusInQueue:
+			inQueue++
+		case endpoint.JobStatusInProgress:
+			inProgress++
+		case endpoint.JobStatusCompleted:
+			completed++
+		case endpoint.JobStatusFailed, endpoint.JobStatusTimedOut:
+			failed++
+		}
+	}
+	workers := s.workers
+	writeJSON(w, endpoint.HealthOutput{
+		Workers: &workers,
+		Jobs: &endpoint.HealthJobOutput{
+			InQueue:    &inQueue,
+			InProgress: &inProgress,
+			Completed:  &completed,
+			Failed:     &failed,
+			Retried:    sdk.Int(0),
+		},
+	})
+}
+
+func (s *Server) handlePurgeQueue(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	removed := 0
+	for _, j := range s.jobs {
+		if !j.purged && j.state(now) == endpoint.JobStatusInQueue {
+			j.purged = true
+			j.cancelled = &now
+			removed++
+		}
+	}
+	writeJSON(w, endpoint.PurgeQueueOutput{Status: sdk.String("completed"), Removed: &removed})
+}
+
+func (s *Server) status(id string) (*endpoint.StatusOutput, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return j.output(time.Now()), true
+}
+
+// stream returns the job status along with the chunks released since the
+// last call.
+func (s *Server) stream(id string) (*endpoint.StatusOutput, []endpoint.StreamResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, nil, false
+	}
+	now := time.Now()
+	released := j.released(now)
+	var chunks []endpoint.StreamResult
+	for _, chunk := range j.script.Chunks[j.streamed:released] {
+		chunks = append(chunks, endpoint.StreamResult{"output": chunk})
+	}
+	j.streamed = released
+	return j.output(now), chunks, true
+}
+
+func (j *job) state(now time.Time) endpoint.JobStatus {
+	if j.cancelled != nil {
+		return endpoint.JobStatusCancelled
+	}
+	elapsed := now.Sub(j.createdAt)
+	switch {
+	case elapsed < j.script.QueueDelay:
+		return endpoint.JobStatusInQueue
+	case j.script.Hang || elapsed < j.script.QueueDelay+j.script.ExecutionTime:
+		return endpoint.JobStatusInProgress
+	case j.script.Error != "":
+		return endpoint.JobStatusFailed
+	case j.script.TimedOut:
+		return endpoint.JobStatusTimedOut
+	}
+	return endpoint.JobStatusCompleted
+}
+
+// released returns how many chunks have been produced by now.
+func (j *job) released(now time.Time) int {
+	total := len(j.script.Chunks)
+	state := j.state(now)
+	switch {
+	case state == endpoint.JobStatusInQueue:
+		return 0
+	case state != endpoint.JobStatusInProgress && j.cancelled == nil:
+		return total
+	case j.script.ExecutionTime <= 0:
+		if j.script.Hang {
+			return total
+		}
+		return j.streamed
+	}
+	end := now
+	if j.cancelled != nil {
+		end = *j.cancelled
+	}
+	running := end.Sub(j.createdAt.Add(j.script.QueueDelay))
+	n := int(float64(total) * float64(running) / float64(j.script.ExecutionTime))
+	if n > total {
+		n = total
+	}
+	if n < j.streamed {
+		n = j.streamed
+	}
+	return n
+}
+
+func (j *job) output(now time.Time) *endpoint.StatusOutput {
+	state := j.state(now)
+	output := &endpoint.StatusOutput{Id: sdk.String(j.id), Status: &state}
+	if state == endpoint.JobStatusInQueue {
+		return output
+	}
+
+	queued := j.script.QueueDelay
+	running := now.Sub(j.createdAt) - queued
+	if j.cancelled != nil {
+		running = j.cancelled.Sub(j.createdAt) - queued
+	} else if !j.script.Hang && running > j.script.ExecutionTime {
+		running = j.script.ExecutionTime
+	}
+	if running < 0 {
+		queued, running = j.cancelled.Sub(j.createdAt), 0
+	}
+	output.DelayTime = sdk.Int(int(queued.Milliseconds()))
+	output.ExecutionTime = sdk.Int(int(running.Milliseconds()))
+	output.Retries = sdk.Int(0)
+
+	switch state {
+	case endpoint.JobStatusCompleted:
+		out := j.script.Output
+		output.Output = &out
+	case endpoint.JobStatusFailed:
+		output.Error = sdk.String(j.script.Error)
+	case endpoint.JobStatusTimedOut:
+		output.Error = sdk.String("job timed out")
+	}
+	return output
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,188 @@
+package endpointtest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+func TestJobStates(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name   string
+		script Script
+		at     time.Duration
+		want   endpoint.JobStatus
+	}{
+		{"queued", Script{QueueDelay: time.Second}, 500 * time.Millisecond, endpoint.JobStatusInQueue},
+		{"running", Script{QueueDelay: time.Second, ExecutionTime: time.Second}, 1500 * time.Millisecond, endpoint.JobStatusInProgress},
+		{"completed", Script{QueueDelay: time.Second, ExecutionTime: time.Second}, 3 * time.Second, endpoint.JobStatusCompleted},
+		{"failed", Script{Error: "boom"}, time.Second, endpoint.JobStatusFailed},
+		{"timed out", Script{TimedOut: true}, time.Second, endpoint.JobStatusTimedOut},
+		{"hang", Script{Hang: true}, time.Hour, endpoint.JobStatusInProgress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &job{script: tt.script, createdAt: start}
+			if got := j.state(start.Add(tt.at)); got != tt.want {
+				t.Errorf("state = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobCancel(t *testing.T) {
+	start := time.Now()
+	cancelled := start.Add(2 * time.Second)
+	j := &job{script: Script{Hang: true, QueueDelay: time.Second}, createdAt: start, cancelled: &cancelled}
+
+	output := j.output(start.Add(time.Hour))
+	if *output.Status != endpoint.JobStatusCancelled {
+		t.Fatalf("status = %s, want %s", *output.Status, endpoint.JobStatusCancelled)
+	}
+	if *output.DelayTime != 1000 || *output.ExecutionTime != 1000 {
+		t.Errorf("delay %d, execution %d, want 1000 and 1000", *output.DelayTime, *output.ExecutionTime)
+	}
+}
+
+func TestJobChunkRelease(t *testing.T) {
+	start := time.Now()
+	j := &job{
+		script:    Script{QueueDelay: time.Second, ExecutionTime: 4 * time.Second, Chunks: []interface{}{1, 2, 3, 4}},
+		createdAt: start,
+	}
+	for _, tt := range []struct {
+		at   time.Duration
+		want int
+	}{
+		{500 * time.Millisecond, 0},
+		{2 * time.Second, 1},
+		{3 * time.Second, 2},
+		{time.Hour, 4},
+	} {
+		if got := j.released(start.Add(tt.at)); got != tt.want {
+			t.Errorf("released at %s = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestHangReleasesChunksUntilCancelled(t *testing.T) {
+	start := time.Now()
+	j := &job{script: Script{Hang: true, Chunks: []interface{}{1, 2}}, createdAt: start}
+	if got := j.released(start.Add(time.Second)); got != 2 {
+		t.Errorf("released = %d, want 2", got)
+	}
+	if got := j.state(start.Add(time.Second)); got != endpoint.JobStatusInProgress {
+		t.Errorf("state = %s, want %s", got, endpoint.JobStatusInProgress)
+	}
+	cancelled := start.Add(time.Second)
+	j.cancelled = &cancelled
+	if got := j.state(start.Add(2 * time.Second)); got != endpoint.JobStatusCancelled {
+		t.Errorf("state after cancel = %s, want %s", got, endpoint.JobStatusCancelled)
+	}
+}
+
+func TestServerLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Script = func(*endpoint.JobInput) Script {
+		return Script{QueueDelay: 30 * time.Millisecond, ExecutionTime: 30 * time.Millisecond, Output: "ok"}
+	}
+	ep, err := endpoint.New(srv.Config(), srv.Option())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: &endpoint.JobInput{Input: map[string]interface{}{"n": 1}}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	seen := map[endpoint.JobStatus]bool{}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := ep.Status(&endpoint.StatusInput{Id: run.Id})
+		if err != nil {
+			t.Fatalf("Status: %v", err)
+		}
+		seen[*status.Status] = true
+		if status.Status.IsTerminal() {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, want := range []endpoint.JobStatus{endpoint.JobStatusInQueue, endpoint.JobStatusInProgress, endpoint.JobStatusCompleted} {
+		if !seen[want] {
+			t.Errorf("status %s never seen, saw %v", want, seen)
+		}
+	}
+}
+
+func TestServerHangUntilCancelled(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Script = func(*endpoint.JobInput) Script { return Script{Hang: true} }
+	ep, err := endpoint.New(srv.Config(), srv.Option())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: &endpoint.JobInput{Input: map[string]interface{}{"n": 1}}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	status, err := ep.Status(&endpoint.StatusInput{Id: run.Id})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if *status.Status != endpoint.JobStatusInProgress {
+		t.Fatalf("status = %s, want %s", *status.Status, endpoint.JobStatusInProgress)
+	}
+	if _, err := ep.Cancel(&endpoint.CancelInput{Id: run.Id}); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	status, err = ep.Status(&endpoint.StatusInput{Id: run.Id})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if *status.Status != endpoint.JobStatusCancelled {
+		t.Errorf("status = %s, want %s", *status.Status, endpoint.JobStatusCancelled)
+	}
+	if got := srv.Requests("cancel"); got != 1 {
+		t.Errorf("cancel requests = %d, want 1", got)
+	}
+}
+
+func TestServerFailNext(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	ep, err := endpoint.New(srv.Config(), srv.Option())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// health is idempotent, so the default retry policy recovers
+	srv.FailNext("health", http.StatusServiceUnavailable, 1)
+
+	if _, err := ep.Health(&endpoint.HealthInput{}); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if got := srv.Requests("health"); got != 2 {
+		t.Errorf("health requests = %d, want 2", got)
+	}
+
+	srv.FailNext("health", http.StatusInternalServerError, 5)
+	opt := srv.Option()
+	opt.RetryPolicy = endpoint.NoRetry()
+	ep, err = endpoint.New(srv.Config(), opt)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	_, err = ep.Health(&endpoint.HealthInput{})
+	var apiErr *endpoint.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("err = %v, want a 500 APIError", err)
+	}
+}
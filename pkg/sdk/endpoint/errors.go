@@ -0,0 +1,154 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnauthorized is matched by API errors for a missing or invalid api key.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is matched by API errors for an unknown endpoint or job.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is matched by API errors returned when the endpoint is
+	// throttling requests.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is matched by API errors for 5xx responses.
+	ErrServer = errors.New("server error")
+	// ErrTimeout is matched when a request or a polling loop ran out of time.
+	ErrTimeout = errors.New("timeout reached")
+)
+
+// AbandonedError is returned by RunSync, StatusSync and Job.Wait when the
+// caller's context ended before the job finished and Option.CancelOnAbandon
+// is set. It matches the context's error and reports the outcome of the
+// cancel request sent for the job.
+type AbandonedError struct {
+	JobId string
+	// Err is why the call stopped, e.g. context.Canceled.
+	Err error
+	// Cancel is the answer to the cancel request, nil if it failed.
+	Cancel *CancelOutput
+	// CancelErr is set when the job could not be cancelled.
+	CancelErr error
+}
+
+func (e *AbandonedError) Error() string {
+	if e.CancelErr != nil {
+		return fmt.Sprintf("%v: cancel of job %s failed: %v", e.Err, e.JobId, e.CancelErr)
+	}
+	return fmt.Sprintf("%v: job %s cancelled", e.Err, e.JobId)
+}
+
+func (e *AbandonedError) Unwrap() error {
+	return e.Err
+}
+
+// APIError is returned when the RunPod API answers with a non 200 status.
+type APIError struct {
+	StatusCode int
+	Status     string
+	// Message is the error message parsed from the response body, if any.
+	Message string
+	Body    []byte
+	URL     string
+	JobId   string
+	// RetryAfter is the delay requested by the server's Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := "response status " + e.Status
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error matching the status code, so callers can
+// use errors.Is(err, ErrUnauthorized) and friends.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+// Retryable reports whether sending the same request again may succeed.
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether err is a transient failure: a retryable API
+// status or a network error. Context cancellation is never retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func newAPIError(resp *http.Response, body []byte, url string, jobId string) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Message:    parseErrorMessage(body),
+		Body:       body,
+		URL:        url,
+		JobId:      jobId,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// parseErrorMessage extracts the message from the bodies RunPod returns on
+// errors, either {"error": "..."}, {"message": "..."} or plain text.
+func parseErrorMessage(body []byte) string {
+	var payload struct {
+		Error   interface{} `json:"error"`
+		Message string      `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		switch v := payload.Error.(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case map[string]interface{}:
+			// OpenAI style {"error": {"message": ...}}
+			if message, ok := v["message"].(string); ok && message != "" {
+				return message
+			}
+			return fmt.Sprint(v)
+		case nil:
+		default:
+			return fmt.Sprint(v)
+		}
+		return payload.Message
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,220 @@
+package endpoint
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type HealthEventType string
+
+const (
+	// HealthWorkersUp is sent when the endpoint goes from no running or idle
+	// worker to at least one.
+	HealthWorkersUp HealthEventType = "WorkersUp"
+	// HealthWorkersDown is sent when the last running or idle worker is gone.
+	HealthWorkersDown HealthEventType = "WorkersDown"
+	// HealthQueueGrowing is sent when more jobs are in queue than at the
+	// previous poll.
+	HealthQueueGrowing HealthEventType = "QueueGrowing"
+	// HealthThrottledRising is sent when more workers are throttled than at
+	// the previous poll.
+	HealthThrottledRising HealthEventType = "ThrottledRising"
+	// HealthJobsFailed is sent when the failed job counter grew.
+	HealthJobsFailed HealthEventType = "JobsFailed"
+	// HealthPollFailed is sent when a health request failed.
+	HealthPollFailed HealthEventType = "PollFailed"
+)
+
+type HealthEvent struct {
+	Type HealthEventType
+	Time time.Time
+	// Previous and Current are the snapshots compared, Current being nil
+	// when the poll failed.
+	Previous *HealthOutput
+	Current  *HealthOutput
+	// Delta is how much the watched counter changed, e.g. the number of new
+	// failed jobs.
+	Delta int
+	// Err is set on HealthPollFailed events.
+	Err error
+}
+
+type HealthWatcherOption struct {
+	// Interval is the time in seconds between two polls.
+	Interval *int `default:"10" min:"1"`
+	// RequestTimeout is the timeout in seconds of each poll. Defaults to the
+	// endpoint's request timeout.
+	RequestTimeout *int `min:"1"`
+	// OnEvent is called with every event, from the watcher's goroutine.
+	OnEvent func(HealthEvent)
+	// EventBuffer is the capacity of the Events channel. Events are dropped
+	// while it is full.
+	EventBuffer *int `default:"16" min:"0"`
+}
+
+// HealthWatcher polls the health of an endpoint and reports meaningful
+// changes as events.
+type HealthWatcher struct {
+	ep     *Endpoint
+	option *HealthWatcherOption
+	events chan HealthEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	latest *HealthOutput
+	at     time.Time
+}
+
+// WatchHealth starts polling the endpoint's health until ctx is done or the
+// watcher is stopped. The first poll is sent right away.
+func (ep *Endpoint) WatchHealth(ctx context.Context, option *HealthWatcherOption) (*HealthWatcher, error) {
+	if option == nil {
+		option = &HealthWatcherOption{}
+	}
+	option, err := prepare(ep, option)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &HealthWatcher{
+		ep:     ep,
+		option: option,
+		events: make(chan HealthEvent, *option.EventBuffer),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w, nil
+}
+
+// Latest returns the last health snapshot and when it was taken, or nil
+// before the first successful poll.
+func (w *HealthWatcher) Latest() (*HealthOutput, time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.latest, w.at
+}
+
+// Events returns the channel events are sent on. It is closed once the
+// watcher stopped.
+func (w *HealthWatcher) Events() <-chan HealthEvent {
+	return w.events
+}
+
+// Stop stops polling and waits for the watcher's goroutine to exit.
+func (w *HealthWatcher) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *HealthWatcher) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.events)
+
+	interval := time.Duration(*w.option.Interval) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *HealthWatcher) poll(ctx context.Context) {
+	current, err := w.ep.HealthWithContext(ctx, &HealthInput{RequestTimeout: w.option.RequestTimeout})
+	now := time.Now()
+	if ctx.Err() != nil {
+		return
+	}
+
+	w.mu.Lock()
+	previous := w.latest
+	if err == nil {
+		w.latest, w.at = current, now
+	}
+	w.mu.Unlock()
+
+	if err != nil {
+		w.emit(HealthEvent{Type: HealthPollFailed, Time: now, Previous: previous, Err: err})
+		return
+	}
+	if previous == nil {
+		return
+	}
+	for _, event := range healthChanges(previous, current) {
+		event.Time, event.Previous, event.Current = now, previous, current
+		w.emit(event)
+	}
+}
+
+func (w *HealthWatcher) emit(event HealthEvent) {
+	if w.option.OnEvent != nil {
+		w.option.OnEvent(event)
+	}
+	select {
+	case w.events <- event:
+	default:
+	}
+}
+
+// healthChanges compares two snapshots. Only the type and delta of the
+// returned events are set.
+func healthChanges(previous, current *HealthOutput) []HealthEvent {
+	var events []HealthEvent
+	before, after := servingWorkers(previous), servingWorkers(current)
+	switch {
+	case before == 0 && after > 0:
+		events = append(events, HealthEvent{Type: HealthWorkersUp, Delta: after})
+	case before > 0 && after == 0:
+		events = append(events, HealthEvent{Type: HealthWorkersDown, Delta: -before})
+	}
+
+	var prevWorkers, curWorkers HealthWorkerOutput
+	if previous.Workers != nil {
+		prevWorkers = *previous.Workers
+	}
+	if current.Workers != nil {
+		curWorkers = *current.Workers
+	}
+	var prevJobs, curJobs HealthJobOutput
+	if previous.Jobs != nil {
+		prevJobs = *previous.Jobs
+	}
+	if current.Jobs != nil {
+		curJobs = *current.Jobs
+	}
+	for _, counter := range []struct {
+		event         HealthEventType
+		before, after *int
+	}{
+		{HealthQueueGrowing, prevJobs.InQueue, curJobs.InQueue},
+		{HealthThrottledRising, prevWorkers.Throttled, curWorkers.Throttled},
+		{HealthJobsFailed, prevJobs.Failed, curJobs.Failed},
+	} {
+		if delta := intValue(counter.after) - intValue(counter.before); delta > 0 {
+			events = append(events, HealthEvent{Type: counter.event, Delta: delta})
+		}
+	}
+	return events
+}
+
+// servingWorkers counts the workers able to take jobs.
+func servingWorkers(health *HealthOutput) int {
+	if health.Workers == nil {
+		return 0
+	}
+	return intValue(health.Workers.Running) + intValue(health.Workers.Idle)
+}
+
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,195 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+// eventLog collects the events passed to OnEvent.
+type eventLog struct {
+	mu     sync.Mutex
+	events []endpoint.HealthEvent
+}
+
+func (l *eventLog) add(event endpoint.HealthEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) deltas() map[endpoint.HealthEventType]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	deltas := map[endpoint.HealthEventType]int{}
+	for _, event := range l.events {
+		deltas[event.Type] = event.Delta
+	}
+	return deltas
+}
+
+func (l *eventLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = nil
+}
+
+func TestWatchHealthEvents(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, nil)
+	srv.SetWorkers(endpoint.HealthWorkerOutput{})
+	var log eventLog
+	w, err := ep.WatchHealth(context.Background(), &endpoint.HealthWatcherOption{Interval: sdk.Int(1), OnEvent: log.add})
+	if err != nil {
+		t.Fatalf("WatchHealth: %v", err)
+	}
+	defer w.Stop()
+
+	// the first poll is sent right away and only sets the baseline
+	time.Sleep(300 * time.Millisecond)
+	if got := srv.Requests("health"); got != 1 {
+		t.Fatalf("health requests after 300ms = %d, want 1", got)
+	}
+	if latest, at := w.Latest(); latest == nil || at.IsZero() {
+		t.Fatalf("Latest = %v, %v, want the first snapshot", latest, at)
+	}
+	if deltas := log.deltas(); len(deltas) != 0 {
+		t.Fatalf("events after the first poll: %v", deltas)
+	}
+
+	srv.SetWorkers(endpoint.HealthWorkerOutput{Idle: sdk.Int(2), Throttled: sdk.Int(1)})
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+	for i := 0; i < 2; i++ {
+		if _, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()}); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	srv.Script = script(endpointtest.Script{Error: "boom"})
+	if _, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	time.Sleep(time.Second)
+	if got := srv.Requests("health"); got != 2 {
+		t.Fatalf("health requests after 1.3s = %d, want 2", got)
+	}
+	want := map[endpoint.HealthEventType]int{
+		endpoint.HealthWorkersUp:       2,
+		endpoint.HealthQueueGrowing:    2,
+		endpoint.HealthThrottledRising: 1,
+		endpoint.HealthJobsFailed:      1,
+	}
+	if got := log.deltas(); !equalDeltas(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+
+	log.reset()
+	srv.SetWorkers(endpoint.HealthWorkerOutput{})
+	time.Sleep(time.Second)
+	want = map[endpoint.HealthEventType]int{endpoint.HealthWorkersDown: -2}
+	if got := log.deltas(); !equalDeltas(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func equalDeltas(a, b map[endpoint.HealthEventType]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWatchHealthPollFailed(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, func(opt *endpoint.Option) {
+		opt.RetryPolicy = endpoint.NoRetry()
+	})
+	srv.FailNext("health", http.StatusInternalServerError, 1)
+
+	w, err := ep.WatchHealth(context.Background(), &endpoint.HealthWatcherOption{Interval: sdk.Int(1)})
+	if err != nil {
+		t.Fatalf("WatchHealth: %v", err)
+	}
+	defer w.Stop()
+
+	select {
+	case event := <-w.Events():
+		var apiErr *endpoint.APIError
+		if event.Type != endpoint.HealthPollFailed || !errors.As(event.Err, &apiErr) || event.Current != nil {
+			t.Fatalf("event = %+v, want a PollFailed event with the API error", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no event for the failed poll")
+	}
+	if latest, _ := w.Latest(); latest != nil {
+		t.Errorf("Latest = %+v after a failed poll, want nil", latest)
+	}
+
+	// the watcher keeps polling after an error
+	time.Sleep(1200 * time.Millisecond)
+	if latest, _ := w.Latest(); latest == nil {
+		t.Errorf("Latest = nil, want the snapshot of the second poll")
+	}
+}
+
+func TestWatchHealthStop(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, nil)
+
+	var log eventLog
+	w, err := ep.WatchHealth(context.Background(), &endpoint.HealthWatcherOption{OnEvent: log.add})
+	if err != nil {
+		t.Fatalf("WatchHealth: %v", err)
+	}
+	w.Stop()
+	// Stop waits for the goroutine, which closes the events channel
+	if _, ok := <-w.Events(); ok {
+		t.Fatalf("Events not closed after Stop")
+	}
+	requests := srv.Requests("health")
+	time.Sleep(50 * time.Millisecond)
+	if got := srv.Requests("health"); got != requests {
+		t.Errorf("health requests went from %d to %d after Stop", requests, got)
+	}
+	w.Stop()
+}
+
+func TestWatchHealthContextDone(t *testing.T) {
+	t.Parallel()
+	_, ep := newTestEndpoint(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w, err := ep.WatchHealth(ctx, &endpoint.HealthWatcherOption{Interval: sdk.Int(60)})
+	if err != nil {
+		t.Fatalf("WatchHealth: %v", err)
+	}
+	cancel()
+	select {
+	case _, ok := <-w.Events():
+		if ok {
+			t.Fatalf("unexpected event")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Events not closed after ctx was cancelled")
+	}
+}
+
+func TestWatchHealthInvalidOption(t *testing.T) {
+	_, ep := newTestEndpoint(t, nil)
+
+	if _, err := ep.WatchHealth(context.Background(), &endpoint.HealthWatcherOption{Interval: sdk.Int(0)}); !errors.Is(err, sdk.ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,43 @@
+package endpoint
+
+import "context"
+
+// OperationInfo describes an Endpoint operation to hooks.
+type OperationInfo struct {
+	Operation  Operation
+	EndpointId string
+	// JobId is empty for Run and RunSync until the result is known, and for
+	// Health and PurgeQueue.
+	JobId string
+}
+
+// OperationHook is called when an Endpoint operation starts, e.g. to open a
+// tracing span covering a RunSync and all its polls. The returned context is
+// used for the operation's requests, and the returned function, if not nil,
+// is called with the operation's result and error once it ends. The result
+// is the operation's output, such as a *RunSyncOutput, and may be a nil
+// pointer.
+type OperationHook func(ctx context.Context, info OperationInfo) (context.Context, func(result interface{}, err error))
+
+func (ep *Endpoint) startOperation(ctx context.Context, op Operation, jobId *string) (context.Context, func(result interface{}, err error)) {
+	if len(ep.hooks) == 0 {
+		return ctx, func(interface{}, error) {}
+	}
+	info := OperationInfo{Operation: op, EndpointId: *ep.EndpointId}
+	if jobId != nil {
+		info.JobId = *jobId
+	}
+	ends := make([]func(interface{}, error), 0, len(ep.hooks))
+	for _, hook := range ep.hooks {
+		var end func(interface{}, error)
+		ctx, end = hook(ctx, info)
+		if end != nil {
+			ends = append(ends, end)
+		}
+	}
+	return ctx, func(result interface{}, err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](result, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Job is a handle on a job submitted to an Endpoint. It remembers the last
+// status seen by any of its calls. A Job is safe for concurrent use.
+type Job struct {
+	Id string
+
+	ep *Endpoint
+
+	mu      sync.Mutex
+	last    *StatusOutput
+	timings JobTimings
+}
+
+// JobTimings records when the client observed each stage of a job, along
+// with the durations reported by RunPod.
+type JobTimings struct {
+	SubmittedAt time.Time
+	// StartedAt is when the job was first seen IN_PROGRESS.
+	StartedAt time.Time
+	// CompletedAt is when a terminal status was first seen.
+	CompletedAt time.Time
+	// DelayTime is the time the job spent in queue, as reported by RunPod.
+	DelayTime time.Duration
+	// ExecutionTime is the time the job spent running, as reported by RunPod.
+	ExecutionTime time.Duration
+}
+
+// Submit queues a job like Run and returns a handle bound to the endpoint.
+func (ep *Endpoint) Submit(ctx context.Context, input *RunInput) (*Job, error) {
+	job, _, err := ep.submit(ctx, input)
+	return job, err
+}
+
+func (ep *Endpoint) submit(ctx context.Context, input *RunInput) (*Job, *RunOutput, error) {
+	submittedAt := time.Now()
+	output, err := ep.RunWithContext(ctx, input)
+	if err != nil {
+		return nil, output, err
+	}
+	if output.Id == nil {
+		return nil, output, errors.New("run response has no job id")
+	}
+	job := ep.Job(*output.Id)
+	job.timings.SubmittedAt = submittedAt
+	job.observe(&StatusOutput{Id: output.Id, Status: output.Status})
+	return job, output, nil
+}
+
+// Job returns a handle for an already submitted job.
+func (ep *Endpoint) Job(id string) *Job {
+	return &Job{Id: id, ep: ep}
+}
+
+// Status fetches the current status of the job.
+func (j *Job) Status(ctx context.Context) (*StatusOutput, error) {
+	output, err := j.ep.StatusWithContext(ctx, &StatusInput{Id: &j.Id})
+	if err != nil {
+		return output, err
+	}
+	j.observe(output)
+	return output, nil
+}
+
+// Wait blocks until the job reaches a terminal status, the API returns an
+// error or ctx is done. Unlike StatusSync it has no timeout of its own.
+func (j *Job) Wait(ctx context.Context) (*StatusOutput, error) {
+	return j.WaitWithStrategy(ctx, nil)
+}
+
+// WaitWithStrategy is like Wait but paces the status polls with strategy
+// instead of Option.PollStrategy.
+func (j *Job) WaitWithStrategy(ctx context.Context, strategy PollStrategy) (*StatusOutput, error) {
+	if last, done := j.Result(); done {
+		return last, nil
+	}
+	for {
+		output, err := j.ep.StatusSyncWithContext(ctx, &StatusSyncInput{Id: &j.Id, PollStrategy: strategy})
+		if output != nil && output.Status != nil {
+			j.observe((*StatusOutput)(output))
+		}
+		if err != nil {
+			// only StatusSync's own timeout starts another wait, a 408 or 504
+			// from the API is returned like any other API error
+			var apiErr *APIError
+			if errors.Is(err, ErrTimeout) && !errors.As(err, &apiErr) && ctx.Err() == nil {
+				continue
+			}
+			return (*StatusOutput)(output), err
+		}
+		return (*StatusOutput)(output), nil
+	}
+}
+
+// Cancel cancels the job.
+func (j *Job) Cancel(ctx context.Context) (*CancelOutput, error) {
+	output, err := j.ep.CancelWithContext(ctx, &CancelInput{Id: &j.Id})
+	if err != nil {
+		return output, err
+	}
+	j.observe(&StatusOutput{
+		DelayTime:     output.DelayTime,
+		Error:         output.Error,
+		ExecutionTime: output.ExecutionTime,
+		Id:            output.Id,
+		Status:        output.Status,
+	})
+	return output, nil
+}
+
+// Stream opens a reader on the job's stream output. Like Wait, it follows
+// the job until it reaches a terminal status or ctx is done.
+func (j *Job) Stream(ctx context.Context) (*StreamReader, error) {
+	follow := true
+	return j.ep.OpenStream(ctx, &StreamInput{Id: &j.Id, Follow: &follow})
+}
+
+// Result returns the last status seen for the job, without calling the API,
+// and whether that status is terminal.
+func (j *Job) Result() (*StatusOutput, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.last == nil {
+		return nil, false
+	}
+	return j.last, isTerminal(j.last.Status)
+}
+
+// Timings returns the client side timings of the job so far.
+func (j *Job) Timings() JobTimings {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.timings
+}
+
+func (j *Job) observe(output *StatusOutput) {
+	if output == nil {
+		return
+	}
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.last = output
+	if output.DelayTime != nil {
+		j.timings.DelayTime = time.Duration(*output.DelayTime) * time.Millisecond
+	}
+	if output.ExecutionTime != nil {
+		j.timings.ExecutionTime = time.Duration(*output.ExecutionTime) * time.Millisecond
+	}
+	if output.Status == nil {
+		return
+	}
+	if *output.Status == JobStatusInProgress && j.timings.StartedAt.IsZero() {
+		j.timings.StartedAt = now
+	}
+	if output.Status.IsTerminal() && j.timings.CompletedAt.IsZero() {
+		j.timings.CompletedAt = now
+	}
+}
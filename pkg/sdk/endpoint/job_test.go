@@ -0,0 +1,59 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+func TestJobWait(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{ExecutionTime: 30 * time.Millisecond, Output: "done"})
+
+	job, err := ep.Submit(context.Background(), &endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	output, err := job.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusCompleted {
+		t.Fatalf("status = %v, want %s", output.Status, endpoint.JobStatusCompleted)
+	}
+	if result, done := job.Result(); !done || result.Output == nil {
+		t.Errorf("Result = %+v, %v, want the completed output", result, done)
+	}
+}
+
+func TestJobWaitGatewayTimeout(t *testing.T) {
+	srv, ep := newTestEndpoint(t, func(opt *endpoint.Option) {
+		opt.RetryPolicy = endpoint.NoRetry()
+	})
+	srv.Script = script(endpointtest.Script{Hang: true})
+	job, err := ep.Submit(context.Background(), &endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	// the gateway never recovers
+	srv.FailNext("status-sync", http.StatusGatewayTimeout, 1<<20)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = job.Wait(ctx)
+	var apiErr *endpoint.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("err = %v, want a 504 APIError", err)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("Wait returned only once ctx ended")
+	}
+	if got := srv.Requests("status-sync"); got != 1 {
+		t.Errorf("status-sync requests = %d, want 1", got)
+	}
+}
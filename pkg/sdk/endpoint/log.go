@@ -0,0 +1,149 @@
+package endpoint
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// LoggingMiddleware logs every attempt of a request at debug level, and
+// failed attempts at warn level. The Authorization header is redacted.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *Request) (*Response, error) {
+			ctx := req.HTTP.Context()
+			attrs := []slog.Attr{
+				slog.String("operation", string(req.Operation)),
+				slog.String("method", req.HTTP.Method),
+				slog.String("url", req.HTTP.URL.String()),
+				slog.Int("attempt", req.Attempt),
+			}
+			if req.JobId != "" {
+				attrs = append(attrs, slog.String("job_id", req.JobId))
+			}
+			logger.LogAttrs(ctx, slog.LevelDebug, "runpod request", append(attrs, redactHeaders(req.HTTP.Header))...)
+
+			start := time.Now()
+			resp, err := next.Do(req)
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+			if resp != nil && resp.HTTP != nil {
+				attrs = append(attrs, slog.Int("status", resp.HTTP.StatusCode))
+			}
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelWarn, "runpod request failed", append(attrs, slog.String("error", err.Error()))...)
+				return resp, err
+			}
+			logger.LogAttrs(ctx, slog.LevelDebug, "runpod response", attrs...)
+			return resp, err
+		})
+	}
+}
+
+// redactHeaders returns the headers as a log group, keeping only the scheme
+// of the Authorization header.
+func redactHeaders(header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if strings.EqualFold(name, "Authorization") {
+			value = redactAuthorization(value)
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+func redactAuthorization(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok {
+		return scheme + " " + redacted
+	}
+	return redacted
+}
+
+// redactURLToken hides the token query parameter of webhook URLs, such as
+// the ones built by webhook.SignedURL.
+func redactURLToken(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	query := u.Query()
+	if !query.Has("token") {
+		return rawURL
+	}
+	query.Set("token", redacted)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// LogValue redacts the access secret when the config is logged with slog.
+func (c S3Config) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 5)
+	if c.AccessId != nil {
+		attrs = append(attrs, slog.String("accessId", *c.AccessId))
+	}
+	if c.AccessSecret != nil {
+		attrs = append(attrs, slog.String("accessSecret", redacted))
+	}
+	if c.BucketName != nil {
+		attrs = append(attrs, slog.String("bucketName", *c.BucketName))
+	}
+	if c.EndpointUrl != nil {
+		attrs = append(attrs, slog.String("endpointUrl", *c.EndpointUrl))
+	}
+	if c.ObjectPath != nil {
+		attrs = append(attrs, slog.String("objectPath", *c.ObjectPath))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue logs the job input with its S3 access secret redacted.
+func (j JobInput) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 5)
+	if j.Input != nil {
+		attrs = append(attrs, slog.Any("input", j.Input))
+	}
+	if j.Policy != nil {
+		attrs = append(attrs, slog.Any("policy", *j.Policy))
+	}
+	if j.S3Config != nil {
+		attrs = append(attrs, slog.Any("s3Config", *j.S3Config))
+	}
+	if j.Webhook != nil {
+		attrs = append(attrs, slog.String("webhook", redactURLToken(*j.Webhook)))
+	}
+	if j.WebhookV2 != nil {
+		attrs = append(attrs, slog.String("webhookV2", redactURLToken(*j.WebhookV2)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// logPoll logs one iteration of a status polling loop.
+func (ep *Endpoint) logPoll(ctx context.Context, op Operation, id *string, iteration int) {
+	if ep.logger == nil || id == nil {
+		return
+	}
+	ep.logger.LogAttrs(ctx, slog.LevelDebug, "runpod polling job status",
+		slog.String("operation", string(op)),
+		slog.String("job_id", *id),
+		slog.Int("iteration", iteration),
+	)
+}
+
+// logTransition logs a change of job status seen by a polling loop.
+func (ep *Endpoint) logTransition(ctx context.Context, id *string, from string, to *JobStatus) {
+	if ep.logger == nil || id == nil || to == nil || string(*to) == from {
+		return
+	}
+	ep.logger.LogAttrs(ctx, slog.LevelInfo, "runpod job status changed",
+		slog.String("endpoint_id", *ep.EndpointId),
+		slog.String("job_id", *id),
+		slog.String("from", from),
+		slog.String("to", string(*to)),
+	)
+}
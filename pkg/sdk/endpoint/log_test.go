@@ -0,0 +1,88 @@
+package endpoint_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+const (
+	s3Secret     = "s3-secret-value"
+	webhookToken = "webhook-token-value"
+)
+
+func secretJobInput() *endpoint.JobInput {
+	return &endpoint.JobInput{
+		Input: map[string]interface{}{"prompt": "hello"},
+		S3Config: &endpoint.S3Config{
+			AccessId:     sdk.String("access-id"),
+			AccessSecret: sdk.String(s3Secret),
+			BucketName:   sdk.String("bucket"),
+		},
+		Webhook:   sdk.String("https://example.com/hook?token=" + webhookToken),
+		WebhookV2: sdk.String("https://example.com/hook/v2?id=1&token=" + webhookToken),
+	}
+}
+
+func TestLogRedactsSecrets(t *testing.T) {
+	for name, newHandler := range map[string]func(*bytes.Buffer) slog.Handler{
+		"json": func(buf *bytes.Buffer) slog.Handler {
+			return slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+		},
+		"text": func(buf *bytes.Buffer) slog.Handler {
+			return slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(newHandler(&buf))
+			srv, ep := newTestEndpoint(t, func(opt *endpoint.Option) {
+				opt.Logger = logger
+			})
+
+			input := secretJobInput()
+			if _, err := ep.RunSync(&endpoint.RunSyncInput{JobInput: input, Timeout: sdk.Int(5)}); err != nil {
+				t.Fatalf("RunSync: %v", err)
+			}
+			// failed attempts are logged at warn level with the error
+			srv.FailNext("health", http.StatusUnauthorized, 1)
+			ep.Health(&endpoint.HealthInput{})
+			logger.InfoContext(context.Background(), "submitting", "job", input)
+
+			out := buf.String()
+			for _, secret := range []string{endpointtest.ApiKey, s3Secret, webhookToken} {
+				if strings.Contains(out, secret) {
+					t.Errorf("log output contains %q:\n%s", secret, out)
+				}
+			}
+			for _, want := range []string{"Bearer [REDACTED]", "runpod request failed", "access-id", "example.com/hook"} {
+				if !strings.Contains(out, want) {
+					t.Errorf("log output does not contain %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestJobInputLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	input := secretJobInput()
+	input.Webhook = sdk.String("://not a url?token=" + webhookToken)
+	logger.Info("job", "input", input, "s3", *input.S3Config)
+
+	out := buf.String()
+	if strings.Contains(out, s3Secret) || strings.Contains(out, webhookToken) {
+		t.Errorf("log output leaks a secret: %s", out)
+	}
+	if !strings.Contains(out, `"accessSecret":"[REDACTED]"`) {
+		t.Errorf("access secret not shown as redacted: %s", out)
+	}
+}
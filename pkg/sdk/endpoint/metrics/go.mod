@@ -0,0 +1,23 @@
+module github.com/runpod/go-sdk/pkg/sdk/endpoint/metrics
+
+go 1.21.3
+
+require (
+	github.com/prometheus/client_golang v1.20.5
+	github.com/runpod/go-sdk/pkg/sdk v0.0.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.4.0 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
+
+replace github.com/runpod/go-sdk/pkg/sdk => ../..
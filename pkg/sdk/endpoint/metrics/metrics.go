@@ -0,0 +1,258 @@
+// Package metrics exposes Prometheus metrics about the calls made by
+// endpoints and the jobs they observe.
+//
+//	collector := metrics.New(nil)
+//	prometheus.MustRegister(collector)
+//	option := &rpEndpoint.Option{EndpointId: sdk.String("ENDPOINT_ID")}
+//	endpoint, err := rpEndpoint.New(cfg, collector.Instrument(option))
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+type Option struct {
+	// Namespace prefixes every metric name.
+	Namespace *string `default:"runpod"`
+	// ConstLabels are added to every metric.
+	ConstLabels prometheus.Labels
+	// RequestBuckets are the buckets of the request latency histogram, in
+	// seconds. Defaults to prometheus.DefBuckets.
+	RequestBuckets []float64
+	// JobBuckets are the buckets of the job delay and execution time
+	// histograms, in seconds.
+	JobBuckets []float64
+}
+
+// Collector records the metrics of every endpoint instrumented with it. It
+// implements prometheus.Collector and is safe for concurrent use.
+type Collector struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	jobs            *prometheus.CounterVec
+	delayTime       *prometheus.HistogramVec
+	executionTime   *prometheus.HistogramVec
+
+	seen *jobSet
+}
+
+var defaultJobBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+// New creates a Collector. input may be nil.
+func New(input *Option) *Collector {
+	var option Option
+	if input != nil {
+		option = *input
+	}
+	// the tags are fixed and nothing is required, so an error is a bug
+	if err := sdk.ApplyDefaults(&option); err != nil {
+		panic(err)
+	}
+	input = &option
+	namespace := *input.Namespace
+	requestBuckets := prometheus.DefBuckets
+	if len(input.RequestBuckets) > 0 {
+		requestBuckets = input.RequestBuckets
+	}
+	jobBuckets := defaultJobBuckets
+	if len(input.JobBuckets) > 0 {
+		jobBuckets = input.JobBuckets
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "requests_total",
+			Help:        "API requests made by the SDK, by operation and HTTP status code.",
+			ConstLabels: input.ConstLabels,
+		}, []string{"endpoint", "operation", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Latency of API requests made by the SDK, by operation.",
+			ConstLabels: input.ConstLabels,
+			Buckets:     requestBuckets,
+		}, []string{"endpoint", "operation"}),
+		jobs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "jobs_total",
+			Help:        "Jobs seen reaching a terminal status, by status.",
+			ConstLabels: input.ConstLabels,
+		}, []string{"endpoint", "status"}),
+		delayTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "job_delay_seconds",
+			Help:        "Time finished jobs spent in queue, as reported by RunPod.",
+			ConstLabels: input.ConstLabels,
+			Buckets:     jobBuckets,
+		}, []string{"endpoint"}),
+		executionTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "job_execution_seconds",
+			Help:        "Time finished jobs spent running, as reported by RunPod.",
+			ConstLabels: input.ConstLabels,
+			Buckets:     jobBuckets,
+		}, []string{"endpoint"}),
+		seen: newJobSet(maxSeenJobs),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.requestDuration.Describe(ch)
+	c.jobs.Describe(ch)
+	c.delayTime.Describe(ch)
+	c.executionTime.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.requestDuration.Collect(ch)
+	c.jobs.Collect(ch)
+	c.delayTime.Collect(ch)
+	c.executionTime.Collect(ch)
+}
+
+// Instrument adds the collector's hook and middleware to option and returns
+// it. The middleware is added to Option.AttemptMiddleware, so retried and
+// throttled attempts are counted.
+func (c *Collector) Instrument(option *endpoint.Option) *endpoint.Option {
+	option.OperationHooks = append(option.OperationHooks, c.OperationHook())
+	option.AttemptMiddleware = append([]endpoint.Middleware{c.Middleware()}, option.AttemptMiddleware...)
+	return option
+}
+
+type endpointIdKey struct{}
+
+// OperationHook records the terminal status and timings of the jobs returned
+// by endpoint operations. Each job is counted once, however many times its
+// final status is fetched.
+func (c *Collector) OperationHook() endpoint.OperationHook {
+	return func(ctx context.Context, info endpoint.OperationInfo) (context.Context, func(interface{}, error)) {
+		ctx = context.WithValue(ctx, endpointIdKey{}, info.EndpointId)
+		return ctx, func(result interface{}, _ error) {
+			c.observeJob(info, result)
+		}
+	}
+}
+
+// Middleware counts every API request and records its latency. Installed as
+// attempt middleware, each attempt of a retried call is counted with its own
+// status code, and the latency excludes backoff and rate limiter waits.
+func (c *Collector) Middleware() endpoint.Middleware {
+	return func(next endpoint.Doer) endpoint.Doer {
+		return endpoint.DoerFunc(func(req *endpoint.Request) (*endpoint.Response, error) {
+			endpointId, _ := req.HTTP.Context().Value(endpointIdKey{}).(string)
+			op := string(req.Operation)
+
+			start := time.Now()
+			resp, err := next.Do(req)
+			c.requestDuration.WithLabelValues(endpointId, op).Observe(time.Since(start).Seconds())
+			c.requests.WithLabelValues(endpointId, op, statusCode(resp, err)).Inc()
+			return resp, err
+		})
+	}
+}
+
+// statusCode is the code label of a request: its HTTP status, or "timeout"
+// or "error" when no response was received.
+func statusCode(resp *endpoint.Response, err error) string {
+	if resp != nil && resp.HTTP != nil {
+		return strconv.Itoa(resp.HTTP.StatusCode)
+	}
+	if errors.Is(err, endpoint.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "error"
+}
+
+func (c *Collector) observeJob(info endpoint.OperationInfo, result interface{}) {
+	var id *string
+	var status *endpoint.JobStatus
+	var delayTime, executionTime *int
+	switch v := result.(type) {
+	case *endpoint.RunSyncOutput:
+		if v != nil {
+			id, status, delayTime, executionTime = v.Id, v.Status, v.DelayTime, v.ExecutionTime
+		}
+	case *endpoint.StatusOutput:
+		if v != nil {
+			id, status, delayTime, executionTime = v.Id, v.Status, v.DelayTime, v.ExecutionTime
+		}
+	case *endpoint.StatusSyncOutput:
+		if v != nil {
+			id, status, delayTime, executionTime = v.Id, v.Status, v.DelayTime, v.ExecutionTime
+		}
+	case *endpoint.CancelOutput:
+		if v != nil {
+			id, status, delayTime, executionTime = v.Id, v.Status, v.DelayTime, v.ExecutionTime
+		}
+	case *endpoint.StreamOutput:
+		if v != nil {
+			status = v.Status
+		}
+	}
+	if status == nil || !status.IsTerminal() {
+		return
+	}
+	jobId := info.JobId
+	if id != nil {
+		jobId = *id
+	}
+	if jobId != "" && !c.seen.add(info.EndpointId+"/"+jobId) {
+		return
+	}
+
+	c.jobs.WithLabelValues(info.EndpointId, string(*status)).Inc()
+	if delayTime != nil {
+		c.delayTime.WithLabelValues(info.EndpointId).Observe(millis(*delayTime))
+	}
+	if executionTime != nil {
+		c.executionTime.WithLabelValues(info.EndpointId).Observe(millis(*executionTime))
+	}
+}
+
+func millis(ms int) float64 {
+	return float64(ms) / 1000
+}
+
+// maxSeenJobs bounds how many finished jobs are remembered for deduplication.
+const maxSeenJobs = 4096
+
+// jobSet remembers the most recent keys added to it, forgetting the oldest
+// once full.
+type jobSet struct {
+	mu    sync.Mutex
+	keys  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newJobSet(size int) *jobSet {
+	return &jobSet{keys: make(map[string]struct{}, size), order: make([]string, size)}
+}
+
+// add reports whether key was not already in the set.
+func (s *jobSet) add(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[key]; ok {
+		return false
+	}
+	if old := s.order[s.next]; old != "" {
+		delete(s.keys, old)
+	}
+	s.order[s.next] = key
+	s.next = (s.next + 1) % len(s.order)
+	s.keys[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,75 @@
+package metrics_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/metrics"
+)
+
+func TestCountsEveryAttempt(t *testing.T) {
+	srv := endpointtest.NewServer()
+	defer srv.Close()
+	collector := metrics.New(nil)
+
+	option := collector.Instrument(srv.Option())
+	option.RetryPolicy = &endpoint.RetryPolicy{MaxAttempts: 3, Multiplier: 1}
+	ep, err := endpoint.New(srv.Config(), option)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	srv.FailNext("health", http.StatusTooManyRequests, 1)
+
+	if _, err := ep.Health(&endpoint.HealthInput{}); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+
+	want := `
+# HELP runpod_requests_total API requests made by the SDK, by operation and HTTP status code.
+# TYPE runpod_requests_total counter
+runpod_requests_total{code="200",endpoint="test-endpoint",operation="Health"} 1
+runpod_requests_total{code="429",endpoint="test-endpoint",operation="Health"} 1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "runpod_requests_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(collector, "runpod_request_duration_seconds"); n != 1 {
+		t.Errorf("got %d duration series, want 1", n)
+	}
+}
+
+func TestCountsJobsOnce(t *testing.T) {
+	srv := endpointtest.NewServer()
+	defer srv.Close()
+	collector := metrics.New(nil)
+	ep, err := endpoint.New(srv.Config(), collector.Instrument(srv.Option()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: &endpoint.JobInput{Input: map[string]interface{}{"n": 1}}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := ep.Status(&endpoint.StatusInput{Id: run.Id}); err != nil {
+			t.Fatalf("Status: %v", err)
+		}
+	}
+	if n := testutil.CollectAndCount(collector, "runpod_jobs_total"); n != 1 {
+		t.Fatalf("got %d job series, want 1", n)
+	}
+	want := `
+# HELP runpod_jobs_total Jobs seen reaching a terminal status, by status.
+# TYPE runpod_jobs_total counter
+runpod_jobs_total{endpoint="test-endpoint",status="COMPLETED"} 1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "runpod_jobs_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,291 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk/ratelimit"
+)
+
+// Request is an API call made by an Endpoint, as seen by middleware.
+type Request struct {
+	Operation Operation
+	// JobId is the job the call is about, empty for Run, Health and
+	// PurgeQueue.
+	JobId string
+	HTTP  *http.Request
+	// Timeout bounds each attempt of the request.
+	Timeout time.Duration
+	// Attempt is 1 for the first attempt and grows with every retry. Once
+	// the call returns it holds the number of attempts made.
+	Attempt int
+	// Result is what the response body is decoded into, if not nil. After
+	// the call returns it holds the decoded output, e.g. a *StatusOutput.
+	Result interface{}
+	// Stream leaves the body of a successful response unread, for streamed
+	// responses such as server-sent events. Timeout then only bounds the wait
+	// for the response headers.
+	Stream bool
+}
+
+// Response is the answer to a Request. Its HTTP body has already been read
+// into Body, unless the request was a Stream one: the HTTP body is then left
+// open for the caller to read and close.
+type Response struct {
+	HTTP *http.Response
+	Body []byte
+}
+
+// Doer sends a Request.
+type Doer interface {
+	Do(req *Request) (*Response, error)
+}
+
+// DoerFunc adapts a function to a Doer.
+type DoerFunc func(req *Request) (*Response, error)
+
+func (f DoerFunc) Do(req *Request) (*Response, error) {
+	return f(req)
+}
+
+// Middleware wraps every request sent by an Endpoint, e.g. to add headers or
+// audit calls. Middleware set on Option.Middleware runs once per call, around
+// the SDK's own retry and rate limit middleware, while Option.AttemptMiddleware
+// runs once per attempt, inside them.
+type Middleware func(next Doer) Doer
+
+// Chain wraps doer with middleware, the first one being the outermost.
+func Chain(doer Doer, middleware ...Middleware) Doer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		doer = middleware[i](doer)
+	}
+	return doer
+}
+
+// RetryMiddleware retries failed attempts according to policy.
+func RetryMiddleware(policy *RetryPolicy) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *Request) (*Response, error) {
+			ctx := req.HTTP.Context()
+			for attempt := 1; ; attempt++ {
+				current := req
+				if attempt > 1 {
+					var err error
+					if current, err = req.retry(attempt); err != nil {
+						return nil, err
+					}
+				}
+				resp, err := next.Do(current)
+				req.Attempt = attempt
+				if !policy.shouldRetry(req.Operation, attempt, err) {
+					return resp, err
+				}
+				if sleepErr := sleepContext(ctx, policy.backoff(attempt, err)); sleepErr != nil {
+					return resp, err
+				}
+			}
+		})
+	}
+}
+
+// RateLimitMiddleware waits for limiter before each attempt.
+func RateLimitMiddleware(limiter ratelimit.Limiter) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *Request) (*Response, error) {
+			if err := limiter.Wait(req.HTTP.Context()); err != nil {
+				return nil, fmt.Errorf("rate limiter: %w", err)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// retry returns a copy of the request for another attempt, with a fresh body.
+func (r *Request) retry(attempt int) (*Request, error) {
+	next := *r
+	next.Attempt = attempt
+	next.HTTP = r.HTTP.Clone(r.HTTP.Context())
+	if r.HTTP.GetBody != nil {
+		body, err := r.HTTP.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("http request body error: %w", err)
+		}
+		next.HTTP.Body = body
+	}
+	return &next, nil
+}
+
+// newDoer builds the request pipeline of an endpoint.
+func newDoer(client *http.Client, retry *RetryPolicy, limiter ratelimit.Limiter, logger *slog.Logger, middleware []Middleware, attemptMiddleware []Middleware) Doer {
+	chain := append([]Middleware{}, middleware...)
+	if retry != nil {
+		chain = append(chain, RetryMiddleware(retry))
+	}
+	if limiter != nil {
+		chain = append(chain, RateLimitMiddleware(limiter))
+	}
+	chain = append(chain, attemptMiddleware...)
+	if logger != nil {
+		chain = append(chain, LoggingMiddleware(logger))
+	}
+	return Chain(&httpDoer{client: client}, chain...)
+}
+
+func (ep *Endpoint) requestDoer() Doer {
+	if ep.doer == nil {
+		return newDoer(ep.httpClient(), DefaultRetryPolicy(), nil, nil, nil, nil)
+	}
+	return ep.doer
+}
+
+// maxErrorBodySize caps how much of an error response is kept on APIError.
+const maxErrorBodySize = 64 << 10
+
+// httpDoer sends a single attempt over HTTP and decodes the response.
+type httpDoer struct {
+	client *http.Client
+}
+
+func (d *httpDoer) Do(req *Request) (*Response, error) {
+	if req.Stream {
+		return d.doStream(req)
+	}
+	httpReq := req.HTTP
+	if req.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(httpReq.Context(), req.Timeout)
+		defer cancel()
+		httpReq = httpReq.WithContext(ctx)
+	}
+
+	resp, err := d.client.Do(httpReq)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errorResponse(req, resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Response{HTTP: resp}, fmt.Errorf("io read error: %w", err)
+	}
+	response := &Response{HTTP: resp, Body: body}
+	if req.Result != nil {
+		if err := json.Unmarshal(body, req.Result); err != nil {
+			return response, fmt.Errorf("json decoder error: %w", err)
+		}
+	}
+	return response, nil
+}
+
+// doStream sends a Stream request. The timeout only runs until the response
+// headers arrive; closing the body releases the request's context.
+func (d *httpDoer) doStream(req *Request) (*Response, error) {
+	ctx, cancel := context.WithCancel(req.HTTP.Context())
+	var timer *time.Timer
+	if req.Timeout > 0 {
+		timer = time.AfterFunc(req.Timeout, cancel)
+	}
+
+	resp, err := d.client.Do(req.HTTP.WithContext(ctx))
+	if timer != nil && !timer.Stop() {
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("sls request error: %w: no response after %s", ErrTimeout, req.Timeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, requestError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer cancel()
+		defer resp.Body.Close()
+		return errorResponse(req, resp)
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return &Response{HTTP: resp}, nil
+}
+
+// cancelBody releases the context of a streamed request once closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func requestError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("sls request error: %w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("sls request error: %w", err)
+}
+
+// errorResponse reads a non 200 response into an APIError.
+func errorResponse(req *Request, resp *http.Response) (*Response, error) {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	// drain so the keep-alive connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return &Response{HTTP: resp, Body: body}, newAPIError(resp, body, req.HTTP.URL.String(), req.JobId)
+}
+
+// getApiResponse sends the request through the endpoint's middleware chain.
+func (ep *Endpoint) getApiResponse(ctx context.Context, input apiRequestInput) ([]byte, error) {
+	resp, err := ep.sendApiRequest(ctx, input, false)
+	if resp == nil {
+		return nil, err
+	}
+	return resp.Body, err
+}
+
+// getApiStream sends a streamed request and returns its open body, which the
+// caller must close.
+func (ep *Endpoint) getApiStream(ctx context.Context, input apiRequestInput) (io.ReadCloser, error) {
+	resp, err := ep.sendApiRequest(ctx, input, true)
+	if err != nil {
+		return nil, err
+	}
+	return resp.HTTP.Body, nil
+}
+
+func (ep *Endpoint) sendApiRequest(ctx context.Context, input apiRequestInput, stream bool) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, input.method, *input.url, bytes.NewReader(input.reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("http request create error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+*input.token)
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	request := &Request{
+		Operation: input.op,
+		HTTP:      req,
+		Timeout:   time.Second * time.Duration(*input.timeout),
+		Attempt:   1,
+		Result:    input.result,
+		Stream:    stream,
+	}
+	if input.jobId != nil {
+		request.JobId = *input.jobId
+	}
+	return ep.requestDoer().Do(request)
+}
@@ -0,0 +1,88 @@
+package endpoint_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+// callLog records the order in which middleware runs.
+type callLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (l *callLog) add(call string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, call)
+}
+
+func (l *callLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.calls...)
+}
+
+// record returns a middleware logging name before and after the rest of the
+// chain, with the attempt number of the request.
+func (l *callLog) record(name string) endpoint.Middleware {
+	return func(next endpoint.Doer) endpoint.Doer {
+		return endpoint.DoerFunc(func(req *endpoint.Request) (*endpoint.Response, error) {
+			l.add(fmt.Sprintf("%s>%d", name, req.Attempt))
+			resp, err := next.Do(req)
+			l.add(fmt.Sprintf("<%s", name))
+			return resp, err
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	var log callLog
+	doer := endpoint.Chain(endpoint.DoerFunc(func(req *endpoint.Request) (*endpoint.Response, error) {
+		log.add("doer")
+		return &endpoint.Response{}, nil
+	}), log.record("first"), log.record("second"), log.record("third"))
+
+	if _, err := doer.Do(&endpoint.Request{Attempt: 1}); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	want := []string{"first>1", "second>1", "third>1", "doer", "<third", "<second", "<first"}
+	if got := log.get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestMiddlewareAroundRetries(t *testing.T) {
+	var log callLog
+	srv, ep := newTestEndpoint(t, func(opt *endpoint.Option) {
+		opt.RetryPolicy = &endpoint.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 1}
+		opt.Middleware = []endpoint.Middleware{log.record("call1"), log.record("call2")}
+		opt.AttemptMiddleware = []endpoint.Middleware{log.record("attempt1"), log.record("attempt2")}
+	})
+	srv.FailNext("health", http.StatusBadGateway, 1)
+
+	if _, err := ep.HealthWithContext(context.Background(), &endpoint.HealthInput{}); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	// Middleware runs once around the retries, AttemptMiddleware once per
+	// attempt, each list in order
+	want := []string{
+		"call1>1", "call2>1",
+		"attempt1>1", "attempt2>1", "<attempt2", "<attempt1",
+		"attempt1>2", "attempt2>2", "<attempt2", "<attempt1",
+		"<call2", "<call1",
+	}
+	if got := log.get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	if got := srv.Requests("health"); got != 2 {
+		t.Errorf("health requests = %d, want 2", got)
+	}
+}
@@ -0,0 +1,108 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// OpenAI is a client for the OpenAI compatible API served by vLLM workers.
+// It shares the endpoint's api key, base url, retries and middleware.
+type OpenAI struct {
+	ep *Endpoint
+}
+
+// OpenAI returns a client for the endpoint's OpenAI compatible routes,
+// /v2/{endpointId}/openai/v1.
+func (ep *Endpoint) OpenAI() *OpenAI {
+	return &OpenAI{ep: ep}
+}
+
+// ChatCompletion sends a chat completion request.
+func (c *OpenAI) ChatCompletion(ctx context.Context, input *ChatCompletionInput) (*ChatCompletionOutput, error) {
+	ctx, end := c.ep.startOperation(ctx, OperationChatCompletion, nil)
+	output, err := c.chatCompletion(ctx, input)
+	end(output, err)
+	return output, err
+}
+
+func (c *OpenAI) chatCompletion(ctx context.Context, input *ChatCompletionInput) (*ChatCompletionOutput, error) {
+	input, err := prepare(c.ep, input)
+	if err != nil {
+		return nil, err
+	}
+	input.Stream = nil
+	input.StreamOptions = nil
+
+	var result ChatCompletionOutput
+	if err := c.post(ctx, OperationChatCompletion, "/chat/completions", input, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Completion sends a legacy text completion request.
+func (c *OpenAI) Completion(ctx context.Context, input *CompletionInput) (*CompletionOutput, error) {
+	ctx, end := c.ep.startOperation(ctx, OperationCompletion, nil)
+	output, err := c.completion(ctx, input)
+	end(output, err)
+	return output, err
+}
+
+func (c *OpenAI) completion(ctx context.Context, input *CompletionInput) (*CompletionOutput, error) {
+	input, err := prepare(c.ep, input)
+	if err != nil {
+		return nil, err
+	}
+	input.Stream = nil
+
+	var result CompletionOutput
+	if err := c.post(ctx, OperationCompletion, "/completions", input, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Models lists the models served by the endpoint.
+func (c *OpenAI) Models(ctx context.Context) (*ModelsOutput, error) {
+	ctx, end := c.ep.startOperation(ctx, OperationModels, nil)
+	output, err := c.models(ctx)
+	end(output, err)
+	return output, err
+}
+
+func (c *OpenAI) models(ctx context.Context) (*ModelsOutput, error) {
+	url := c.url("/models")
+	timeout := c.timeout()
+
+	var result ModelsOutput
+	_, err := c.ep.getApiResponse(ctx, apiRequestInput{op: OperationModels, method: "GET", url: &url, token: c.ep.apiKey, timeout: &timeout, result: &result})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *OpenAI) post(ctx context.Context, op Operation, path string, input interface{}, result interface{}) error {
+	reqBody, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("json marshal error: %w", err)
+	}
+	url := c.url(path)
+	timeout := c.timeout()
+	_, err = c.ep.getApiResponse(ctx, apiRequestInput{op: op, method: "POST", url: &url, reqBody: reqBody, token: c.ep.apiKey, timeout: &timeout, result: result})
+	return err
+}
+
+func (c *OpenAI) url(path string) string {
+	return *c.ep.EndpointUrl + "/" + *c.ep.EndpointId + "/openai/v1" + path
+}
+
+// timeout bounds each request in seconds. Generations can be long, so the
+// endpoint's sync timeout is used.
+func (c *OpenAI) timeout() int {
+	if c.ep.syncTimeoutDefault != nil {
+		return *c.ep.syncTimeoutDefault
+	}
+	return 90
+}
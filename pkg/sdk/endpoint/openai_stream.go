@@ -0,0 +1,186 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/sse"
+)
+
+// doneData is the data of the event ending an OpenAI stream.
+const doneData = "[DONE]"
+
+type ChatCompletionChunk struct {
+	Id                *string                     `json:"id,omitempty"`
+	Object            *string                     `json:"object,omitempty"`
+	Created           *int64                      `json:"created,omitempty"`
+	Model             *string                     `json:"model,omitempty"`
+	SystemFingerprint *string                     `json:"system_fingerprint,omitempty"`
+	Choices           []ChatCompletionChunkChoice `json:"choices,omitempty"`
+	// Usage is only set on the last chunk, which has no choices.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+type ChatCompletionChunkChoice struct {
+	Index *int `json:"index,omitempty"`
+	// Delta holds the new content, or parts of tool calls, of the choice.
+	Delta *ChatMessage `json:"delta,omitempty"`
+	// FinishReason is set on the last chunk of the choice.
+	FinishReason *string `json:"finish_reason,omitempty"`
+}
+
+// ChatCompletionStream reads the chunks of a streamed chat completion.
+type ChatCompletionStream struct {
+	ctx    context.Context
+	body   io.ReadCloser
+	events *sse.Reader
+	stop   func() bool
+
+	cur    ChatCompletionChunk
+	usage  *Usage
+	err    error
+	done   bool
+	closed bool
+	end    func(result interface{}, err error)
+}
+
+// ChatCompletionStream sends a chat completion request with stream set and
+// returns a reader over the generated chunks. Cancelling ctx closes the
+// connection, stopping the generation. The stream must be closed.
+//
+//	stream, err := endpoint.OpenAI().ChatCompletionStream(ctx, input)
+//	if err != nil { ... }
+//	defer stream.Close()
+//	for stream.Next() {
+//		for _, choice := range stream.Chunk().Choices { ... }
+//	}
+//	if err := stream.Err(); err != nil { ... }
+func (c *OpenAI) ChatCompletionStream(ctx context.Context, input *ChatCompletionInput) (*ChatCompletionStream, error) {
+	ctx, end := c.ep.startOperation(ctx, OperationChatCompletion, nil)
+	stream, err := c.chatCompletionStream(ctx, input)
+	if err != nil {
+		end(nil, err)
+		return nil, err
+	}
+	stream.end = end
+	return stream, nil
+}
+
+func (c *OpenAI) chatCompletionStream(ctx context.Context, input *ChatCompletionInput) (*ChatCompletionStream, error) {
+	input, err := prepare(c.ep, input)
+	if err != nil {
+		return nil, err
+	}
+	stream := true
+	input.Stream = &stream
+	if input.StreamOptions == nil {
+		input.StreamOptions = &StreamOptions{IncludeUsage: &stream}
+	}
+
+	reqBody, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal error: %w", err)
+	}
+	url := c.url("/chat/completions")
+	timeout := c.timeout()
+	body, err := c.ep.getApiStream(ctx, apiRequestInput{op: OperationChatCompletion, method: "POST", url: &url, reqBody: reqBody, token: c.ep.apiKey, timeout: &timeout})
+	if err != nil {
+		return nil, err
+	}
+	return &ChatCompletionStream{
+		ctx:    ctx,
+		body:   body,
+		events: sse.NewReader(body),
+		// unblock a pending read as soon as ctx is done
+		stop: context.AfterFunc(ctx, func() { body.Close() }),
+	}, nil
+}
+
+// Next advances to the next chunk. It returns false once the stream ended or
+// an error occurred.
+func (s *ChatCompletionStream) Next() bool {
+	if s.done || s.err != nil || s.closed {
+		return false
+	}
+	for {
+		event, err := s.events.Next()
+		if err != nil {
+			switch {
+			case s.ctx.Err() != nil:
+				s.err = s.ctx.Err()
+			case errors.Is(err, io.EOF):
+				s.err = fmt.Errorf("chat completion stream ended before %s: %w", doneData, io.ErrUnexpectedEOF)
+			default:
+				s.err = fmt.Errorf("chat completion stream read error: %w", err)
+			}
+			s.finish()
+			return false
+		}
+		if event.Data == doneData {
+			s.done = true
+			s.finish()
+			return false
+		}
+		if event.Event != "message" && event.Event != "error" {
+			continue
+		}
+
+		var chunk struct {
+			ChatCompletionChunk
+			Error interface{} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+			s.err = fmt.Errorf("json decoder error: %w", err)
+			s.finish()
+			return false
+		}
+		if chunk.Error != nil || event.Event == "error" {
+			s.err = fmt.Errorf("chat completion stream error: %s", parseErrorMessage([]byte(event.Data)))
+			s.finish()
+			return false
+		}
+		s.cur = chunk.ChatCompletionChunk
+		if s.cur.Usage != nil {
+			s.usage = s.cur.Usage
+		}
+		return true
+	}
+}
+
+// Chunk returns the chunk read by the last call to Next.
+func (s *ChatCompletionStream) Chunk() ChatCompletionChunk {
+	return s.cur
+}
+
+// Usage returns the token usage of the completion, sent with the last chunk
+// unless StreamOptions disabled it.
+func (s *ChatCompletionStream) Usage() *Usage {
+	return s.usage
+}
+
+// Err returns the error that stopped Next, if any.
+func (s *ChatCompletionStream) Err() error {
+	return s.err
+}
+
+// Close closes the connection, stopping the generation if it is still
+// running.
+func (s *ChatCompletionStream) Close() error {
+	s.closed = true
+	s.finish()
+	return nil
+}
+
+// finish releases the connection and ends the operation started by
+// ChatCompletionStream, once.
+func (s *ChatCompletionStream) finish() {
+	s.stop()
+	s.body.Close()
+	if s.end != nil {
+		s.end(&s.cur, s.err)
+		s.end = nil
+	}
+}
@@ -0,0 +1,171 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+// newChatServer serves handler as the chat completions route of an endpoint.
+func newChatServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *endpoint.Endpoint {
+	t.Helper()
+	return newOpenAIServer(t, map[string]http.HandlerFunc{
+		"/chat/completions": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			handler(w, r)
+		},
+	})
+}
+
+func chatInput() *endpoint.ChatCompletionInput {
+	return &endpoint.ChatCompletionInput{
+		Model:    sdk.String("model"),
+		Messages: []endpoint.ChatMessage{{Role: sdk.String(endpoint.RoleUser), Content: sdk.String("hi")}},
+	}
+}
+
+func chunk(content string) string {
+	return fmt.Sprintf("data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", content)
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "done",
+			body: chunk("Hel") + ": keep-alive\n\n" + chunk("lo") + "data: {\"id\":\"1\",\"usage\":{\"total_tokens\":3}}\n\ndata: [DONE]\n\n",
+			want: "Hello",
+		},
+		{
+			name: "CRLF",
+			body: strings.ReplaceAll(chunk("a")+chunk("b")+"data: [DONE]\n\n", "\n", "\r\n"),
+			want: "ab",
+		},
+		{
+			name:    "error event",
+			body:    chunk("a") + "event: error\ndata: {\"error\":{\"message\":\"overloaded\"}}\n\n",
+			want:    "a",
+			wantErr: "overloaded",
+		},
+		{
+			name:    "error in data",
+			body:    "data: {\"error\":{\"message\":\"bad model\"}}\n\n",
+			wantErr: "bad model",
+		},
+		{
+			name:    "truncated",
+			body:    chunk("a") + "data: {\"id\":",
+			want:    "a",
+			wantErr: io.ErrUnexpectedEOF.Error(),
+		},
+		{
+			name:    "invalid json",
+			body:    "data: {\n\n",
+			wantErr: "json decoder error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := newChatServer(t, func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, tt.body)
+			})
+			stream, err := ep.OpenAI().ChatCompletionStream(context.Background(), chatInput())
+			if err != nil {
+				t.Fatalf("ChatCompletionStream: %v", err)
+			}
+			defer stream.Close()
+
+			var content strings.Builder
+			for stream.Next() {
+				for _, choice := range stream.Chunk().Choices {
+					if choice.Delta != nil && choice.Delta.Content != nil {
+						content.WriteString(*choice.Delta.Content)
+					}
+				}
+			}
+			if content.String() != tt.want {
+				t.Errorf("content = %q, want %q", content.String(), tt.want)
+			}
+			err = stream.Err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Err: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Err = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if stream.Next() {
+				t.Errorf("Next returned true after the stream ended")
+			}
+		})
+	}
+}
+
+func TestChatCompletionStreamUsage(t *testing.T) {
+	ep := newChatServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, chunk("a")+"data: {\"id\":\"1\",\"usage\":{\"prompt_tokens\":1,\"completion_tokens\":2,\"total_tokens\":3}}\n\ndata: [DONE]\n\n")
+	})
+	stream, err := ep.OpenAI().ChatCompletionStream(context.Background(), chatInput())
+	if err != nil {
+		t.Fatalf("ChatCompletionStream: %v", err)
+	}
+	defer stream.Close()
+	for stream.Next() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if usage := stream.Usage(); usage == nil || usage.TotalTokens == nil || *usage.TotalTokens != 3 {
+		t.Errorf("usage = %+v, want 3 total tokens", usage)
+	}
+}
+
+func TestChatCompletionStreamContextCancelled(t *testing.T) {
+	disconnected := make(chan struct{})
+	ep := newChatServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, chunk("a"))
+		w.(http.Flusher).Flush()
+		// keep generating until the client goes away
+		<-r.Context().Done()
+		close(disconnected)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := ep.OpenAI().ChatCompletionStream(ctx, chatInput())
+	if err != nil {
+		t.Fatalf("ChatCompletionStream: %v", err)
+	}
+	defer stream.Close()
+	if !stream.Next() {
+		t.Fatalf("Next returned false: %v", stream.Err())
+	}
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if stream.Next() {
+		t.Fatalf("Next returned true after ctx was cancelled")
+	}
+	if err := stream.Err(); err != ctx.Err() {
+		t.Errorf("Err = %v, want %v", err, ctx.Err())
+	}
+	if !errors.Is(stream.Err(), context.Canceled) {
+		t.Errorf("Err = %v, want context.Canceled", stream.Err())
+	}
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Errorf("connection was not closed after ctx was cancelled")
+	}
+}
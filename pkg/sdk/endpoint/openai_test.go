@@ -0,0 +1,207 @@
+package endpoint_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/config"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+// newOpenAIServer serves routes, keyed by their path under /openai/v1, as
+// the OpenAI compatible API of an endpoint.
+func newOpenAIServer(t *testing.T, routes map[string]http.HandlerFunc) *endpoint.Endpoint {
+	t.Helper()
+	const prefix = "/chat-endpoint/openai/v1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		route, ok := routes[strings.TrimPrefix(r.URL.Path, prefix)]
+		if !ok || !strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		route(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	ep, err := endpoint.New(&config.Config{ApiKey: sdk.String("key")}, &endpoint.Option{
+		EndpointId:  sdk.String("chat-endpoint"),
+		EndpointUrl: sdk.String(srv.URL),
+		RetryPolicy: &endpoint.RetryPolicy{MaxAttempts: 3},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return ep
+}
+
+// decodeBody decodes a request body into a generic map, so absent fields can
+// be told apart from zero ones.
+func decodeBody(t *testing.T, r *http.Request) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("decode request body: %v", err)
+	}
+	return body
+}
+
+func TestChatCompletion(t *testing.T) {
+	var body map[string]interface{}
+	ep := newOpenAIServer(t, map[string]http.HandlerFunc{
+		"/chat/completions": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("method = %s, want POST", r.Method)
+			}
+			body = decodeBody(t, r)
+			io.WriteString(w, `{
+				"id": "chatcmpl-1",
+				"object": "chat.completion",
+				"model": "model",
+				"choices": [{
+					"index": 0,
+					"message": {
+						"role": "assistant",
+						"tool_calls": [{"id": "call-1", "type": "function", "function": {"name": "weather", "arguments": "{\"city\":\"Paris\"}"}}]
+					},
+					"finish_reason": "tool_calls"
+				}],
+				"usage": {"prompt_tokens": 5, "completion_tokens": 7, "total_tokens": 12}
+			}`)
+		},
+	})
+
+	input := chatInput()
+	input.MaxTokens = sdk.Int(64)
+	input.Stream = sdk.Bool(true)
+	output, err := ep.OpenAI().ChatCompletion(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+
+	if body["model"] != "model" || body["max_tokens"] != 64.0 {
+		t.Errorf("request body = %v", body)
+	}
+	if _, ok := body["stream"]; ok {
+		t.Errorf("stream was sent: %v", body)
+	}
+	if input.Stream == nil || !*input.Stream {
+		t.Errorf("the caller's input was modified")
+	}
+
+	if len(output.Choices) != 1 || output.Choices[0].FinishReason == nil || *output.Choices[0].FinishReason != endpoint.FinishReasonToolCalls {
+		t.Fatalf("choices = %+v, want one tool call choice", output.Choices)
+	}
+	calls := output.Choices[0].Message.ToolCalls
+	if len(calls) != 1 || *calls[0].Function.Name != "weather" || *calls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if output.Usage == nil || *output.Usage.TotalTokens != 12 {
+		t.Errorf("usage = %+v, want 12 total tokens", output.Usage)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	var body map[string]interface{}
+	ep := newOpenAIServer(t, map[string]http.HandlerFunc{
+		"/completions": func(w http.ResponseWriter, r *http.Request) {
+			body = decodeBody(t, r)
+			io.WriteString(w, `{"id":"cmpl-1","object":"text_completion","choices":[{"index":0,"text":" world","finish_reason":"length"},{"index":1,"text":" there","finish_reason":"stop"}]}`)
+		},
+	})
+
+	output, err := ep.OpenAI().Completion(context.Background(), &endpoint.CompletionInput{
+		Model:  sdk.String("model"),
+		Prompt: []string{"hello", "hi"},
+		Stream: sdk.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("Completion: %v", err)
+	}
+	if prompts, ok := body["prompt"].([]interface{}); !ok || len(prompts) != 2 {
+		t.Errorf("prompt = %v, want both prompts", body["prompt"])
+	}
+	if _, ok := body["stream"]; ok {
+		t.Errorf("stream was sent: %v", body)
+	}
+	if len(output.Choices) != 2 || *output.Choices[0].Text != " world" || *output.Choices[1].FinishReason != endpoint.FinishReasonStop {
+		t.Errorf("choices = %+v", output.Choices)
+	}
+}
+
+func TestModels(t *testing.T) {
+	ep := newOpenAIServer(t, map[string]http.HandlerFunc{
+		"/models": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				t.Errorf("method = %s, want GET", r.Method)
+			}
+			io.WriteString(w, `{"object":"list","data":[{"id":"meta-llama/Meta-Llama-3-8B-Instruct","object":"model","owned_by":"vllm"}]}`)
+		},
+	})
+
+	output, err := ep.OpenAI().Models(context.Background())
+	if err != nil {
+		t.Fatalf("Models: %v", err)
+	}
+	if len(output.Data) != 1 || *output.Data[0].Id != "meta-llama/Meta-Llama-3-8B-Instruct" || *output.Data[0].OwnedBy != "vllm" {
+		t.Errorf("models = %+v", output.Data)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	var chatCalls, modelCalls atomic.Int32
+	ep := newOpenAIServer(t, map[string]http.HandlerFunc{
+		"/chat/completions": func(w http.ResponseWriter, r *http.Request) {
+			chatCalls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+		"/completions": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":{"message":"max_tokens is too large","type":"invalid_request_error"}}`)
+		},
+		"/models": func(w http.ResponseWriter, r *http.Request) {
+			if modelCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			io.WriteString(w, `{"object":"list","data":[]}`)
+		},
+	})
+	openai := ep.OpenAI()
+
+	// a generation may have started, so a chat completion is not sent again
+	_, err := openai.ChatCompletion(context.Background(), chatInput())
+	var apiErr *endpoint.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("ChatCompletion err = %v, want a 502 APIError", err)
+	}
+	if got := chatCalls.Load(); got != 1 {
+		t.Errorf("chat completion requests = %d, want 1", got)
+	}
+
+	_, err = openai.Completion(context.Background(), &endpoint.CompletionInput{Model: sdk.String("model"), Prompt: "hi"})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "max_tokens is too large" {
+		t.Errorf("Completion err = %v, want the OpenAI error message", err)
+	}
+
+	if _, err := openai.Models(context.Background()); err != nil {
+		t.Errorf("Models: %v", err)
+	}
+	if got := modelCalls.Load(); got != 2 {
+		t.Errorf("models requests = %d, want 2", got)
+	}
+
+	if _, err := openai.ChatCompletion(context.Background(), &endpoint.ChatCompletionInput{Model: sdk.String("model")}); !errors.Is(err, sdk.ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput without messages", err)
+	}
+}
@@ -0,0 +1,170 @@
+package endpoint
+
+// Message roles of a chat completion.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Finish reasons of a completion choice.
+const (
+	FinishReasonStop      = "stop"
+	FinishReasonLength    = "length"
+	FinishReasonToolCalls = "tool_calls"
+)
+
+type ChatCompletionInput struct {
+	Model    *string       `json:"model" required:"true"`
+	Messages []ChatMessage `json:"messages" required:"true"`
+
+	MaxTokens        *int               `json:"max_tokens,omitempty" min:"1"`
+	Temperature      *float64           `json:"temperature,omitempty" min:"0"`
+	TopP             *float64           `json:"top_p,omitempty" min:"0"`
+	N                *int               `json:"n,omitempty" min:"1"`
+	Stop             []string           `json:"stop,omitempty"`
+	PresencePenalty  *float64           `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64           `json:"frequency_penalty,omitempty"`
+	LogitBias        map[string]float64 `json:"logit_bias,omitempty"`
+	Seed             *int               `json:"seed,omitempty"`
+	User             *string            `json:"user,omitempty"`
+	ResponseFormat   *ResponseFormat    `json:"response_format,omitempty"`
+
+	Tools []Tool `json:"tools,omitempty"`
+	// ToolChoice is "none", "auto", "required" or a ToolChoice naming the
+	// function to call.
+	ToolChoice interface{} `json:"tool_choice,omitempty"`
+
+	// Stream and StreamOptions are set by the streaming methods.
+	Stream        *bool          `json:"stream,omitempty"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+type ChatMessage struct {
+	Role *string `json:"role,omitempty"`
+	// Content is nil on assistant messages that only call tools. Only text
+	// is supported: multimodal content, an array of text and image parts,
+	// cannot be sent.
+	Content    *string    `json:"content,omitempty"`
+	Name       *string    `json:"name,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallId *string    `json:"tool_call_id,omitempty"`
+}
+
+type ResponseFormat struct {
+	// Type is "text" or "json_object".
+	Type *string `json:"type,omitempty"`
+}
+
+type Tool struct {
+	// Type is "function".
+	Type     *string             `json:"type"`
+	Function *FunctionDefinition `json:"function"`
+}
+
+type FunctionDefinition struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description,omitempty"`
+	// Parameters is the JSON schema of the function arguments.
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type ToolChoice struct {
+	Type     *string           `json:"type"`
+	Function *ToolChoiceTarget `json:"function"`
+}
+
+type ToolChoiceTarget struct {
+	Name *string `json:"name"`
+}
+
+type ToolCall struct {
+	// Index identifies the call across stream chunks.
+	Index    *int          `json:"index,omitempty"`
+	Id       *string       `json:"id,omitempty"`
+	Type     *string       `json:"type,omitempty"`
+	Function *FunctionCall `json:"function,omitempty"`
+}
+
+type FunctionCall struct {
+	Name *string `json:"name,omitempty"`
+	// Arguments is the JSON encoded arguments generated by the model.
+	Arguments *string `json:"arguments,omitempty"`
+}
+
+type StreamOptions struct {
+	IncludeUsage *bool `json:"include_usage,omitempty"`
+}
+
+type Usage struct {
+	PromptTokens     *int `json:"prompt_tokens,omitempty"`
+	CompletionTokens *int `json:"completion_tokens,omitempty"`
+	TotalTokens      *int `json:"total_tokens,omitempty"`
+}
+
+type ChatCompletionOutput struct {
+	Id                *string                `json:"id,omitempty"`
+	Object            *string                `json:"object,omitempty"`
+	Created           *int64                 `json:"created,omitempty"`
+	Model             *string                `json:"model,omitempty"`
+	SystemFingerprint *string                `json:"system_fingerprint,omitempty"`
+	Choices           []ChatCompletionChoice `json:"choices,omitempty"`
+	Usage             *Usage                 `json:"usage,omitempty"`
+}
+
+type ChatCompletionChoice struct {
+	Index        *int         `json:"index,omitempty"`
+	Message      *ChatMessage `json:"message,omitempty"`
+	FinishReason *string      `json:"finish_reason,omitempty"`
+}
+
+type CompletionInput struct {
+	Model *string `json:"model" required:"true"`
+	// Prompt is a string or a list of strings.
+	Prompt interface{} `json:"prompt" required:"true"`
+
+	MaxTokens        *int               `json:"max_tokens,omitempty" min:"1"`
+	Temperature      *float64           `json:"temperature,omitempty" min:"0"`
+	TopP             *float64           `json:"top_p,omitempty" min:"0"`
+	N                *int               `json:"n,omitempty" min:"1"`
+	Stop             []string           `json:"stop,omitempty"`
+	PresencePenalty  *float64           `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64           `json:"frequency_penalty,omitempty"`
+	LogitBias        map[string]float64 `json:"logit_bias,omitempty"`
+	Echo             *bool              `json:"echo,omitempty"`
+	Seed             *int               `json:"seed,omitempty"`
+	User             *string            `json:"user,omitempty"`
+
+	// Stream is cleared by Completion, the SDK does not stream text
+	// completions.
+	Stream *bool `json:"stream,omitempty"`
+}
+
+type CompletionOutput struct {
+	Id                *string            `json:"id,omitempty"`
+	Object            *string            `json:"object,omitempty"`
+	Created           *int64             `json:"created,omitempty"`
+	Model             *string            `json:"model,omitempty"`
+	SystemFingerprint *string            `json:"system_fingerprint,omitempty"`
+	Choices           []CompletionChoice `json:"choices,omitempty"`
+	Usage             *Usage             `json:"usage,omitempty"`
+}
+
+type CompletionChoice struct {
+	Index        *int    `json:"index,omitempty"`
+	Text         *string `json:"text,omitempty"`
+	FinishReason *string `json:"finish_reason,omitempty"`
+}
+
+type ModelsOutput struct {
+	Object *string `json:"object,omitempty"`
+	Data   []Model `json:"data,omitempty"`
+}
+
+type Model struct {
+	Id      *string `json:"id,omitempty"`
+	Object  *string `json:"object,omitempty"`
+	Created *int64  `json:"created,omitempty"`
+	OwnedBy *string `json:"owned_by,omitempty"`
+}
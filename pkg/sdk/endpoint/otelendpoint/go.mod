@@ -0,0 +1,21 @@
+module github.com/runpod/go-sdk/pkg/sdk/endpoint/otelendpoint
+
+go 1.21.3
+
+require (
+	github.com/runpod/go-sdk/pkg/sdk v0.0.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.4.0 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+)
+
+replace github.com/runpod/go-sdk/pkg/sdk => ../..
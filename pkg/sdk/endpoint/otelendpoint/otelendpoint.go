@@ -0,0 +1,189 @@
+// Package otelendpoint adds OpenTelemetry tracing to an endpoint. Every
+// operation, such as a RunSync, gets a span with a child span per HTTP call,
+// retries included, and the trace context is propagated in the outgoing
+// request headers.
+//
+//	option := &rpEndpoint.Option{EndpointId: sdk.String("ENDPOINT_ID")}
+//	endpoint, err := rpEndpoint.New(cfg, otelendpoint.Instrument(option, nil))
+package otelendpoint
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+const instrumentationName = "github.com/runpod/go-sdk/pkg/sdk/endpoint/otelendpoint"
+
+const (
+	EndpointIdKey    = attribute.Key("runpod.endpoint.id")
+	JobIdKey         = attribute.Key("runpod.job.id")
+	JobStatusKey     = attribute.Key("runpod.job.status")
+	DelayTimeKey     = attribute.Key("runpod.job.delay_time_ms")
+	ExecutionTimeKey = attribute.Key("runpod.job.execution_time_ms")
+	RetriesKey       = attribute.Key("runpod.job.retries")
+	OperationKey     = attribute.Key("runpod.operation")
+)
+
+type Option struct {
+	// TracerProvider defaults to the global provider.
+	TracerProvider trace.TracerProvider
+	// Propagator defaults to the global text map propagator.
+	Propagator propagation.TextMapPropagator
+}
+
+// Instrument adds the tracing hook and middleware to option and returns it.
+// The middleware is added to Option.AttemptMiddleware, so every attempt of a
+// retried call gets its own span. input may be nil.
+func Instrument(option *endpoint.Option, input *Option) *endpoint.Option {
+	option.OperationHooks = append(option.OperationHooks, OperationHook(input))
+	option.AttemptMiddleware = append([]endpoint.Middleware{Middleware(input)}, option.AttemptMiddleware...)
+	return option
+}
+
+// OperationHook opens a span for every endpoint operation.
+func OperationHook(input *Option) endpoint.OperationHook {
+	tracer := tracerFor(input)
+	return func(ctx context.Context, info endpoint.OperationInfo) (context.Context, func(interface{}, error)) {
+		attrs := []attribute.KeyValue{
+			OperationKey.String(string(info.Operation)),
+			EndpointIdKey.String(info.EndpointId),
+		}
+		if info.JobId != "" {
+			attrs = append(attrs, JobIdKey.String(info.JobId))
+		}
+		ctx, span := tracer.Start(ctx, "runpod."+string(info.Operation),
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(attrs...),
+		)
+		return ctx, func(result interface{}, err error) {
+			span.SetAttributes(resultAttributes(result)...)
+			recordError(span, err)
+			span.End()
+		}
+	}
+}
+
+// Middleware opens a client span for every HTTP call and injects the trace
+// context into its headers. Installed as attempt middleware, retries are
+// marked with http.request.resend_count.
+func Middleware(input *Option) endpoint.Middleware {
+	tracer := tracerFor(input)
+	propagator := otel.GetTextMapPropagator()
+	if input != nil && input.Propagator != nil {
+		propagator = input.Propagator
+	}
+	return func(next endpoint.Doer) endpoint.Doer {
+		return endpoint.DoerFunc(func(req *endpoint.Request) (*endpoint.Response, error) {
+			attrs := []attribute.KeyValue{
+				OperationKey.String(string(req.Operation)),
+				attribute.String("http.request.method", req.HTTP.Method),
+				attribute.String("url.path", req.HTTP.URL.Path),
+				attribute.String("server.address", req.HTTP.URL.Hostname()),
+			}
+			if req.JobId != "" {
+				attrs = append(attrs, JobIdKey.String(req.JobId))
+			}
+			ctx, span := tracer.Start(req.HTTP.Context(), fmt.Sprintf("%s %s", req.HTTP.Method, req.Operation),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			// send a copy so a retry of req is not parented to this span
+			traced := *req
+			traced.HTTP = req.HTTP.Clone(ctx)
+			propagator.Inject(ctx, propagation.HeaderCarrier(traced.HTTP.Header))
+
+			resp, err := next.Do(&traced)
+			if resp != nil && resp.HTTP != nil {
+				span.SetAttributes(attribute.Int("http.response.status_code", resp.HTTP.StatusCode))
+			}
+			if req.Attempt > 1 {
+				span.SetAttributes(attribute.Int("http.request.resend_count", req.Attempt-1))
+			}
+			if err == nil {
+				span.SetAttributes(resultAttributes(req.Result)...)
+			}
+			recordError(span, err)
+			return resp, err
+		})
+	}
+}
+
+func tracerFor(input *Option) trace.Tracer {
+	provider := otel.GetTracerProvider()
+	if input != nil && input.TracerProvider != nil {
+		provider = input.TracerProvider
+	}
+	return provider.Tracer(instrumentationName)
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// resultAttributes extracts the job attributes from an operation output.
+func resultAttributes(result interface{}) []attribute.KeyValue {
+	var id, jobError *string
+	var status *endpoint.JobStatus
+	var delayTime, executionTime, retries *int
+	switch v := result.(type) {
+	case *endpoint.RunOutput:
+		if v != nil {
+			id, status = v.Id, v.Status
+		}
+	case *endpoint.RunSyncOutput:
+		if v != nil {
+			id, status, jobError, delayTime, executionTime, retries = v.Id, v.Status, v.Error, v.DelayTime, v.ExecutionTime, v.Retries
+		}
+	case *endpoint.StatusOutput:
+		if v != nil {
+			id, status, jobError, delayTime, executionTime, retries = v.Id, v.Status, v.Error, v.DelayTime, v.ExecutionTime, v.Retries
+		}
+	case *endpoint.StatusSyncOutput:
+		if v != nil {
+			id, status, jobError, delayTime, executionTime, retries = v.Id, v.Status, v.Error, v.DelayTime, v.ExecutionTime, v.Retries
+		}
+	case *endpoint.CancelOutput:
+		if v != nil {
+			id, status, jobError, delayTime, executionTime = v.Id, v.Status, v.Error, v.DelayTime, v.ExecutionTime
+		}
+	case *endpoint.StreamOutput:
+		if v != nil {
+			status, jobError = v.Status, v.Error
+		}
+	}
+
+	var attrs []attribute.KeyValue
+	if id != nil {
+		attrs = append(attrs, JobIdKey.String(*id))
+	}
+	if status != nil {
+		attrs = append(attrs, JobStatusKey.String(string(*status)))
+	}
+	if jobError != nil {
+		attrs = append(attrs, attribute.String("runpod.job.error", *jobError))
+	}
+	if delayTime != nil {
+		attrs = append(attrs, DelayTimeKey.Int(*delayTime))
+	}
+	if executionTime != nil {
+		attrs = append(attrs, ExecutionTimeKey.Int(*executionTime))
+	}
+	if retries != nil {
+		attrs = append(attrs, RetriesKey.Int(*retries))
+	}
+	return attrs
+}
@@ -0,0 +1,108 @@
+package otelendpoint_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/otelendpoint"
+)
+
+func TestSpanPerAttempt(t *testing.T) {
+	srv := endpointtest.NewServer()
+	defer srv.Close()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	option := otelendpoint.Instrument(srv.Option(), &otelendpoint.Option{TracerProvider: provider})
+	option.RetryPolicy = &endpoint.RetryPolicy{MaxAttempts: 3, Multiplier: 1}
+	ep, err := endpoint.New(srv.Config(), option)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	srv.FailNext("health", http.StatusTooManyRequests, 1)
+
+	if _, err := ep.Health(&endpoint.HealthInput{}); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+
+	var operation sdktrace.ReadOnlySpan
+	var attempts []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.SpanKind() {
+		case trace.SpanKindInternal:
+			operation = span
+		case trace.SpanKindClient:
+			attempts = append(attempts, span)
+		}
+	}
+	if operation == nil || operation.Name() != "runpod.Health" {
+		t.Fatalf("no runpod.Health operation span")
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("got %d client spans, want 2", len(attempts))
+	}
+	for i, span := range attempts {
+		if span.Parent().SpanID() != operation.SpanContext().SpanID() {
+			t.Errorf("attempt %d is not a child of the operation span", i+1)
+		}
+		attrs := map[string]int64{}
+		for _, kv := range span.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.AsInt64()
+		}
+		wantCode := []int64{http.StatusTooManyRequests, http.StatusOK}[i]
+		if attrs["http.response.status_code"] != wantCode {
+			t.Errorf("attempt %d status code = %d, want %d", i+1, attrs["http.response.status_code"], wantCode)
+		}
+		if attrs["http.request.resend_count"] != int64(i) {
+			t.Errorf("attempt %d resend count = %d, want %d", i+1, attrs["http.request.resend_count"], i)
+		}
+	}
+}
+
+func TestCancelledStreamIsAnError(t *testing.T) {
+	srv := endpointtest.NewServer()
+	defer srv.Close()
+	srv.Script = func(*endpoint.JobInput) endpointtest.Script {
+		return endpointtest.Script{Hang: true, Chunks: []interface{}{"a", "b"}}
+	}
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ep, err := endpoint.New(srv.Config(), otelendpoint.Instrument(srv.Option(), &otelendpoint.Option{TracerProvider: provider}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	run, err := ep.Run(&endpoint.RunInput{JobInput: &endpoint.JobInput{Input: map[string]interface{}{"n": 1}}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// nobody reads the results, so the stream is cancelled while sending one
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = ep.StreamWithContext(ctx, &endpoint.StreamInput{Id: run.Id, Follow: sdk.Bool(true), PollWait: sdk.Int(1)}, make(chan endpoint.StreamResult))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	for _, span := range recorder.Ended() {
+		if span.Name() != "runpod.Stream" {
+			continue
+		}
+		if span.Status().Code != codes.Error {
+			t.Errorf("stream span status = %v, want %v", span.Status().Code, codes.Error)
+		}
+		return
+	}
+	t.Fatalf("no runpod.Stream span")
+}
@@ -0,0 +1,153 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxSyncWait is the longest RunPod holds a /status-sync request.
+const maxSyncWait = 90 * time.Second
+
+// minPollDelay is the shortest time between two polls of a job, whatever the
+// strategy asks for, so a zero value strategy does not flood the API.
+const minPollDelay = 100 * time.Millisecond
+
+// PollStrategy paces the status requests sent by RunSync, StatusSync and
+// Job.Wait while a job is not finished.
+type PollStrategy interface {
+	// Next returns the poll to send for attempt, counted from 1, elapsed
+	// being the time since the wait started.
+	Next(attempt int, elapsed time.Duration) Poll
+}
+
+// Poll is the next status request a PollStrategy asks for: how long to wait
+// before sending it, and whether the server may hold it.
+type Poll struct {
+	// Delay is the minimum time between the start of the previous request
+	// for the job and this poll, so a request answered early does not make
+	// the loop spin. Delays below 100ms are raised to 100ms.
+	Delay time.Duration
+	// Wait is how long the server may hold the poll until the job finishes,
+	// using /status-sync, capped at 90 seconds. Zero polls /status, which
+	// answers right away.
+	Wait time.Duration
+}
+
+// FixedInterval polls /status every Interval.
+type FixedInterval struct {
+	Interval time.Duration
+}
+
+func (s FixedInterval) Next(attempt int, elapsed time.Duration) Poll {
+	return Poll{Delay: s.Interval}
+}
+
+// ExponentialBackoff polls /status, starting after Initial, or 100ms when
+// zero, and multiplying the interval by Multiplier after every poll, up to
+// Max.
+type ExponentialBackoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+func (s ExponentialBackoff) Next(attempt int, elapsed time.Duration) Poll {
+	multiplier := s.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(max(s.Initial, minPollDelay)) * math.Pow(multiplier, float64(attempt-1))
+	if s.Max > 0 && delay > float64(s.Max) {
+		delay = float64(s.Max)
+	}
+	return Poll{Delay: time.Duration(delay)}
+}
+
+// LongPoll polls /status-sync, letting the server hold each request up to
+// Wait. MinInterval spaces polls the server answered early, e.g. on errors.
+type LongPoll struct {
+	Wait        time.Duration
+	MinInterval time.Duration
+}
+
+func (s LongPoll) Next(attempt int, elapsed time.Duration) Poll {
+	return Poll{Delay: s.MinInterval, Wait: s.Wait}
+}
+
+// DefaultPollStrategy is used when neither the input nor Option set a
+// PollStrategy.
+func DefaultPollStrategy() PollStrategy {
+	return LongPoll{Wait: maxSyncWait, MinInterval: time.Second}
+}
+
+func (ep *Endpoint) pollStrategy(strategy PollStrategy) PollStrategy {
+	if strategy != nil {
+		return strategy
+	}
+	if ep.defaultPollStrategy != nil {
+		return ep.defaultPollStrategy
+	}
+	return DefaultPollStrategy()
+}
+
+// pollStatus polls job id into result until it reaches a terminal status,
+// fails, or ctx is done. start is when the wait started and last when the
+// previous request for the job was sent, if any. parent is the caller's
+// context, ctx adding the call's timeout.
+func (ep *Endpoint) pollStatus(parent, ctx context.Context, op Operation, id *string, strategy PollStrategy, start, last time.Time, result *StatusOutput) error {
+	for attempt := 1; ; attempt++ {
+		poll := strategy.Next(attempt, time.Since(start))
+		poll.Delay = max(poll.Delay, minPollDelay)
+		if ctx.Err() != nil || sleepContext(ctx, time.Until(last.Add(poll.Delay))) != nil {
+			return ep.abandoned(parent, id, result.Status, ctxDoneError(parent))
+		}
+
+		ep.logPoll(ctx, op, id, attempt)
+		prev := statusString(result.Status)
+		last = time.Now()
+		err := ep.poll(ctx, id, poll.Wait, result)
+		ep.logTransition(ctx, id, prev, result.Status)
+		if err != nil {
+			if parent.Err() != nil {
+				return ep.abandoned(parent, id, result.Status, parent.Err())
+			}
+			return err
+		}
+		if isTerminal(result.Status) {
+			return nil
+		} else if result.Error != nil {
+			return nil
+		}
+	}
+}
+
+// poll sends a single status request for job id, using /status-sync when
+// wait is positive and /status otherwise. wait is shortened to fit in ctx.
+func (ep *Endpoint) poll(ctx context.Context, id *string, wait time.Duration, result *StatusOutput) error {
+	if wait <= 0 {
+		output, err := ep.status(ctx, &StatusInput{Id: id})
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("ctx %w", ErrTimeout)
+			}
+			return err
+		}
+		*result = *output
+		return nil
+	}
+
+	wait = min(wait, maxSyncWait)
+	if deadline, ok := ctx.Deadline(); ok {
+		// leave the request time to come back before the deadline
+		wait = min(wait, time.Until(deadline)-2*time.Second)
+	}
+	wait = max(wait, time.Millisecond)
+	url, err := getStatusSyncURL(ep, id, int(wait/time.Millisecond))
+	if err != nil {
+		return err
+	}
+	reqTimeout := int((wait+time.Second-1)/time.Second) + 2
+	return statusSyncApiCall(ctx, ep, url, id, &reqTimeout, result)
+}
@@ -0,0 +1,78 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+func TestPollStrategyNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy endpoint.PollStrategy
+		attempt  int
+		want     endpoint.Poll
+	}{
+		{"fixed", endpoint.FixedInterval{Interval: time.Second}, 5, endpoint.Poll{Delay: time.Second}},
+		{"backoff first", endpoint.ExponentialBackoff{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2}, 1, endpoint.Poll{Delay: time.Second}},
+		{"backoff third", endpoint.ExponentialBackoff{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2}, 3, endpoint.Poll{Delay: 4 * time.Second}},
+		{"backoff capped", endpoint.ExponentialBackoff{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2}, 10, endpoint.Poll{Delay: 10 * time.Second}},
+		{"backoff uncapped", endpoint.ExponentialBackoff{Initial: time.Second, Multiplier: 2}, 10, endpoint.Poll{Delay: 512 * time.Second}},
+		{"backoff without multiplier", endpoint.ExponentialBackoff{Initial: time.Second}, 10, endpoint.Poll{Delay: time.Second}},
+		{"backoff zero initial", endpoint.ExponentialBackoff{Multiplier: 2}, 1, endpoint.Poll{Delay: 100 * time.Millisecond}},
+		{"backoff zero initial grows", endpoint.ExponentialBackoff{Multiplier: 2}, 4, endpoint.Poll{Delay: 800 * time.Millisecond}},
+		{"long poll", endpoint.LongPoll{Wait: 30 * time.Second, MinInterval: time.Second}, 3, endpoint.Poll{Delay: time.Second, Wait: 30 * time.Second}},
+		{"default", endpoint.DefaultPollStrategy(), 1, endpoint.Poll{Delay: time.Second, Wait: 90 * time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.strategy.Next(tt.attempt, time.Minute); got != tt.want {
+				t.Errorf("Next(%d) = %+v, want %+v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollPacing(t *testing.T) {
+	const wait = 550 * time.Millisecond
+	tests := []struct {
+		name     string
+		strategy endpoint.PollStrategy
+		// polls bounds the number of status requests sent within wait
+		min, max int
+	}{
+		{"zero fixed interval", endpoint.FixedInterval{}, 3, 6},
+		{"negative fixed interval", endpoint.FixedInterval{Interval: -time.Second}, 3, 6},
+		{"zero backoff", endpoint.ExponentialBackoff{}, 3, 6},
+		{"zero long poll", endpoint.LongPoll{}, 3, 6},
+		{"fixed interval", endpoint.FixedInterval{Interval: 200 * time.Millisecond}, 2, 3},
+		{"backoff", endpoint.ExponentialBackoff{Initial: 100 * time.Millisecond, Multiplier: 2}, 2, 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			srv, ep := newTestEndpoint(t, nil)
+			srv.Script = script(endpointtest.Script{Hang: true})
+			job, err := ep.Submit(context.Background(), &endpoint.RunInput{JobInput: jobInput()})
+			if err != nil {
+				t.Fatalf("Submit: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), wait)
+			defer cancel()
+			_, err = job.WaitWithStrategy(ctx, tt.strategy)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+			}
+			polls := srv.Requests("status") + srv.Requests("status-sync")
+			if polls < tt.min || polls > tt.max {
+				t.Errorf("sent %d polls in %s, want between %d and %d", polls, wait, tt.min, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type WaitReadyOption struct {
+	// PollInterval is the time in seconds between two health checks.
+	PollInterval *int `default:"2" min:"1"`
+	// WarmUp, if set, is submitted as a job when the endpoint is not ready at
+	// the first check, to make it scale up.
+	WarmUp *JobInput
+	// RequestTimeout is the timeout in seconds of each request. Defaults to
+	// the endpoint's request timeout.
+	RequestTimeout *int `min:"1"`
+}
+
+type WaitReadyOutput struct {
+	// Elapsed is how long the wait took.
+	Elapsed time.Duration
+	// Health is the last health snapshot seen.
+	Health *HealthOutput
+	// Polls is the number of health checks made.
+	Polls int
+	// WarmUpJob is the warm-up job, if one was submitted. It is not waited
+	// for.
+	WarmUpJob *Job
+}
+
+// WaitReady blocks until the endpoint has at least minIdleOrReady idle or
+// ready workers, or ctx is done. Workers are counted from Health as the
+// larger of the idle and ready counters. The output is returned with ctx's
+// error too, telling how long the wait lasted.
+func (ep *Endpoint) WaitReady(ctx context.Context, minIdleOrReady int, option *WaitReadyOption) (*WaitReadyOutput, error) {
+	if option == nil {
+		option = &WaitReadyOption{}
+	}
+	option, err := prepare(ep, option)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	output := &WaitReadyOutput{}
+	interval := time.Duration(*option.PollInterval) * time.Second
+	for {
+		health, err := ep.HealthWithContext(ctx, &HealthInput{RequestTimeout: option.RequestTimeout})
+		output.Polls++
+		output.Elapsed = time.Since(start)
+		if err != nil && ctx.Err() == nil && !IsRetryable(err) {
+			return output, err
+		}
+		if err == nil {
+			output.Health = health
+			if readyWorkers(health) >= minIdleOrReady {
+				return output, nil
+			}
+		}
+
+		if option.WarmUp != nil && output.WarmUpJob == nil && ctx.Err() == nil {
+			job, err := ep.Submit(ctx, &RunInput{JobInput: option.WarmUp, RequestTimeout: option.RequestTimeout})
+			if err != nil && ctx.Err() == nil {
+				return output, fmt.Errorf("warm-up job: %w", err)
+			}
+			output.WarmUpJob = job
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			output.Elapsed = time.Since(start)
+			return output, err
+		}
+	}
+}
+
+// readyWorkers counts the workers able to take a job right away.
+func readyWorkers(health *HealthOutput) int {
+	if health.Workers == nil {
+		return 0
+	}
+	return max(intValue(health.Workers.Idle), intValue(health.Workers.Ready))
+}
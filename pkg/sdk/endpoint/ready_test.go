@@ -0,0 +1,115 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+func TestWaitReady(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, nil)
+	srv.SetWorkers(endpoint.HealthWorkerOutput{})
+	srv.Script = script(endpointtest.Script{QueueDelay: time.Hour})
+	// workers come up between the second and the third check
+	time.AfterFunc(1500*time.Millisecond, func() {
+		srv.SetWorkers(endpoint.HealthWorkerOutput{Idle: sdk.Int(1), Ready: sdk.Int(2)})
+	})
+
+	output, err := ep.WaitReady(context.Background(), 2, &endpoint.WaitReadyOption{
+		PollInterval: sdk.Int(1),
+		WarmUp:       jobInput(),
+	})
+	if err != nil {
+		t.Fatalf("WaitReady: %v", err)
+	}
+	if output.Polls != 3 {
+		t.Errorf("polls = %d, want 3", output.Polls)
+	}
+	if output.Health == nil || *output.Health.Workers.Ready != 2 {
+		t.Errorf("health = %+v, want the ready snapshot", output.Health)
+	}
+	if output.Elapsed < 2*time.Second {
+		t.Errorf("elapsed = %s, want at least 2s", output.Elapsed)
+	}
+	// the warm-up job is submitted once, after the first check
+	if output.WarmUpJob == nil || output.WarmUpJob.Id == "" {
+		t.Errorf("no warm-up job")
+	}
+	if got := srv.Requests("run"); got != 1 {
+		t.Errorf("run requests = %d, want 1", got)
+	}
+}
+
+func TestWaitReadyAlreadyReady(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, nil)
+
+	output, err := ep.WaitReady(context.Background(), 1, &endpoint.WaitReadyOption{WarmUp: jobInput()})
+	if err != nil {
+		t.Fatalf("WaitReady: %v", err)
+	}
+	if output.Polls != 1 || output.WarmUpJob != nil {
+		t.Errorf("polls = %d, warm-up job %v, want 1 poll and no warm-up", output.Polls, output.WarmUpJob)
+	}
+	if got := srv.Requests("run"); got != 0 {
+		t.Errorf("run requests = %d, want 0", got)
+	}
+}
+
+func TestWaitReadyContextDone(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, nil)
+	srv.SetWorkers(endpoint.HealthWorkerOutput{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	output, err := ep.WaitReady(ctx, 1, &endpoint.WaitReadyOption{PollInterval: sdk.Int(1)})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if output == nil || output.Polls != 1 {
+		t.Fatalf("output = %+v, want 1 poll", output)
+	}
+	if output.Elapsed < 500*time.Millisecond || output.Elapsed > time.Second {
+		t.Errorf("elapsed = %s, want about 500ms", output.Elapsed)
+	}
+}
+
+func TestWaitReadyHealthError(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, nil)
+	srv.FailNext("health", http.StatusUnauthorized, 1)
+
+	output, err := ep.WaitReady(context.Background(), 1, nil)
+	var apiErr *endpoint.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want a 401 APIError", err)
+	}
+	if output.Polls != 1 {
+		t.Errorf("polls = %d, want 1", output.Polls)
+	}
+}
+
+func TestWaitReadyRetryableError(t *testing.T) {
+	t.Parallel()
+	srv, ep := newTestEndpoint(t, func(opt *endpoint.Option) {
+		opt.RetryPolicy = endpoint.NoRetry()
+	})
+	srv.FailNext("health", http.StatusServiceUnavailable, 1)
+
+	// a transient failure is checked again at the next interval
+	output, err := ep.WaitReady(context.Background(), 1, &endpoint.WaitReadyOption{PollInterval: sdk.Int(1)})
+	if err != nil {
+		t.Fatalf("WaitReady: %v", err)
+	}
+	if output.Polls != 2 {
+		t.Errorf("polls = %d, want 2", output.Polls)
+	}
+}
@@ -0,0 +1,169 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Operation names an Endpoint call. It decides whether a failed request may
+// be sent again.
+type Operation string
+
+const (
+	OperationRun        Operation = "Run"
+	OperationRunSync    Operation = "RunSync"
+	OperationStatus     Operation = "Status"
+	OperationStatusSync Operation = "StatusSync"
+	OperationHealth     Operation = "Health"
+	OperationPurgeQueue Operation = "PurgeQueue"
+	OperationCancel     Operation = "Cancel"
+	OperationStream     Operation = "Stream"
+
+	OperationChatCompletion Operation = "ChatCompletion"
+	OperationCompletion     Operation = "Completion"
+	OperationModels         Operation = "Models"
+)
+
+// Idempotent reports whether repeating the operation has no extra effect.
+// Run, RunSync and the OpenAI completions start a new generation each time
+// they reach the API.
+func (op Operation) Idempotent() bool {
+	switch op {
+	case OperationRun, OperationRunSync, OperationChatCompletion, OperationCompletion:
+		return false
+	}
+	return true
+}
+
+// RetryPolicy controls how a single API request is retried. Each HTTP call of
+// an operation is retried on its own, so a failed poll inside RunSync is
+// repeated without restarting the job.
+//
+// Idempotent operations are retried on any retryable status or network
+// error. Non idempotent operations such as Run are only retried when the
+// request was rejected before reaching a worker: a 429 response or a failed
+// connection attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first one included.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. Retry-After is not capped.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt.
+	Multiplier float64
+	// Jitter randomly shortens each backoff by up to this fraction (0-1).
+	Jitter float64
+	// RetryableStatusCodes overrides the default set of retried statuses.
+	RetryableStatusCodes []int
+	// IgnoreRetryAfter disables waiting for the server's Retry-After header.
+	IgnoreRetryAfter bool
+}
+
+// DefaultRetryPolicy is used by endpoints created without Option.RetryPolicy.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// NoRetry disables retries.
+func NoRetry() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 1}
+}
+
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	if p.RetryableStatusCodes == nil {
+		return (&APIError{StatusCode: code}).Retryable()
+	}
+	for _, c := range p.RetryableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldRetry reports whether err, returned by attempt (starting at 1), may
+// be retried for op.
+func (p *RetryPolicy) shouldRetry(op Operation, attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts || err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if !op.Idempotent() {
+			return apiErr.StatusCode == http.StatusTooManyRequests && p.retryableStatus(apiErr.StatusCode)
+		}
+		return p.retryableStatus(apiErr.StatusCode)
+	}
+	if !op.Idempotent() {
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// backoff returns the wait before the attempt following attempt.
+func (p *RetryPolicy) backoff(attempt int, err error) time.Duration {
+	d := float64(p.InitialBackoff)
+	if p.Multiplier > 0 {
+		d *= math.Pow(p.Multiplier, float64(attempt-1))
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	wait := time.Duration(d)
+
+	var apiErr *APIError
+	if !p.IgnoreRetryAfter && errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+		wait = apiErr.RetryAfter
+	}
+	return wait
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	read := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+	tests := []struct {
+		name    string
+		err     error
+		attempt int
+		// want lists the operations err is retried for, the others must not be
+		want []Operation
+	}{
+		{"429", &APIError{StatusCode: http.StatusTooManyRequests}, 1, allOperations},
+		{"502", &APIError{StatusCode: http.StatusBadGateway}, 1, idempotentOperations},
+		{"503", &APIError{StatusCode: http.StatusServiceUnavailable}, 1, idempotentOperations},
+		{"400", &APIError{StatusCode: http.StatusBadRequest}, 1, nil},
+		{"404", &APIError{StatusCode: http.StatusNotFound}, 1, nil},
+		{"wrapped 429", fmt.Errorf("run: %w", &APIError{StatusCode: http.StatusTooManyRequests}), 1, allOperations},
+		{"dial error", dial, 1, allOperations},
+		{"read error", read, 1, idempotentOperations},
+		{"context canceled", context.Canceled, 1, nil},
+		{"wrapped context canceled", fmt.Errorf("status: %w", context.Canceled), 1, nil},
+		{"other error", io.ErrUnexpectedEOF, 1, nil},
+		{"last attempt", &APIError{StatusCode: http.StatusTooManyRequests}, policy.MaxAttempts, nil},
+		{"nil error", nil, 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := map[Operation]bool{}
+			for _, op := range tt.want {
+				want[op] = true
+			}
+			for _, op := range allOperations {
+				if got := policy.shouldRetry(op, tt.attempt, tt.err); got != want[op] {
+					t.Errorf("%s: shouldRetry = %v, want %v", op, got, want[op])
+				}
+			}
+		})
+	}
+}
+
+var allOperations = []Operation{
+	OperationRun, OperationRunSync, OperationStatus, OperationStatusSync, OperationHealth,
+	OperationPurgeQueue, OperationCancel, OperationStream,
+	OperationChatCompletion, OperationCompletion, OperationModels,
+}
+
+var idempotentOperations = []Operation{
+	OperationStatus, OperationStatusSync, OperationHealth, OperationPurgeQueue,
+	OperationCancel, OperationStream, OperationModels,
+}
+
+func TestShouldRetryPolicy(t *testing.T) {
+	apiErr := &APIError{StatusCode: http.StatusInternalServerError}
+	if (*RetryPolicy)(nil).shouldRetry(OperationStatus, 1, apiErr) {
+		t.Errorf("nil policy retried")
+	}
+	if NoRetry().shouldRetry(OperationStatus, 1, apiErr) {
+		t.Errorf("NoRetry retried")
+	}
+	custom := &RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: []int{http.StatusConflict}}
+	if !custom.shouldRetry(OperationStatus, 1, &APIError{StatusCode: http.StatusConflict}) {
+		t.Errorf("custom status code not retried")
+	}
+	if custom.shouldRetry(OperationStatus, 1, apiErr) {
+		t.Errorf("status code outside the custom set retried")
+	}
+	if custom.shouldRetry(OperationRun, 1, &APIError{StatusCode: http.StatusTooManyRequests}) {
+		t.Errorf("429 retried for Run although it is not in the custom set")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"empty", "", 0, 0},
+		{"seconds", "3", 3 * time.Second, 3 * time.Second},
+		{"zero", "0", 0, 0},
+		{"negative", "-5", 0, 0},
+		{"http date", time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), 8 * time.Second, 10 * time.Second},
+		{"past http date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+		{"invalid", "soon", 0, 0},
+		{"fraction", "1.5", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRetryAfter(tt.value)
+			if got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %s, want between %s and %s", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: time.Second,
+	} {
+		if got := policy.backoff(attempt, nil); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(2, nil); got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("jittered backoff = %s, want between 100ms and 200ms", got)
+		}
+	}
+}
+
+func TestBackoffRetryAfter(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	err := fmt.Errorf("health: %w", &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Second})
+
+	if got := policy.backoff(1, err); got != 5*time.Second {
+		t.Errorf("backoff = %s, want the 5s Retry-After, uncapped", got)
+	}
+	if got := policy.backoff(1, &APIError{RetryAfter: 10 * time.Millisecond}); got != 100*time.Millisecond {
+		t.Errorf("backoff = %s, want 100ms when Retry-After is shorter", got)
+	}
+	policy.IgnoreRetryAfter = true
+	if got := policy.backoff(1, err); got != 100*time.Millisecond {
+		t.Errorf("backoff = %s, want 100ms with IgnoreRetryAfter", got)
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sleepContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Package sse reads server-sent events, as sent by OpenAI compatible APIs
+// when streaming completions.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxLineSize bounds a single line of the stream.
+const maxLineSize = 4 << 20
+
+type Event struct {
+	// Event is the event type, "message" when the server did not set one.
+	Event string
+	// Data is the event payload, its data lines joined by newlines.
+	Data string
+	// Id is the last event id seen on the stream.
+	Id string
+	// Retry is the reconnection time asked for by the server, in
+	// milliseconds, or 0.
+	Retry int
+}
+
+// Reader parses events from a stream.
+type Reader struct {
+	scanner *bufio.Scanner
+	lastId  string
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+	scanner.Split(scanLines)
+	return &Reader{scanner: scanner}
+}
+
+// Next returns the next event. It returns io.EOF once the stream ended, an
+// event cut short by the end of the stream being discarded.
+func (r *Reader) Next() (Event, error) {
+	var event Event
+	var data strings.Builder
+	hasData := false
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if !hasData {
+				// nothing to dispatch, e.g. after a comment
+				event = Event{}
+				continue
+			}
+			event.Data = data.String()
+			event.Id = r.lastId
+			if event.Event == "" {
+				event.Event = "message"
+			}
+			return event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				r.lastId = value
+			}
+		case "retry":
+			if retry, err := strconv.Atoi(value); err == nil {
+				event.Retry = retry
+			}
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+// scanLines splits on \n, \r\n and \r, as allowed by the SSE format.
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if !atEOF {
+				// wait to know whether a \n follows
+				return 0, nil, nil
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
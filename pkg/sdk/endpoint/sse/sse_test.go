@@ -0,0 +1,151 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func readAll(r *Reader) ([]Event, error) {
+	var events []Event
+	for {
+		event, err := r.Next()
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+}
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Event
+	}{
+		{
+			name:  "single event",
+			input: "data: hello\n\n",
+			want:  []Event{{Event: "message", Data: "hello"}},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "event: delta\r\ndata: a\r\n\r\ndata: b\r\n\r\n",
+			want:  []Event{{Event: "delta", Data: "a"}, {Event: "message", Data: "b"}},
+		},
+		{
+			name:  "bare CR line endings",
+			input: "data: a\r\rdata: b\r\r",
+			want:  []Event{{Event: "message", Data: "a"}, {Event: "message", Data: "b"}},
+		},
+		{
+			name:  "mixed line endings",
+			input: "data: a\r\ndata: b\rdata: c\n\r\n",
+			want:  []Event{{Event: "message", Data: "a\nb\nc"}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: {\"a\":\ndata: 1}\n\n",
+			want:  []Event{{Event: "message", Data: "{\"a\":\n1}"}},
+		},
+		{
+			name:  "empty data line",
+			input: "data\ndata:\ndata: x\n\n",
+			want:  []Event{{Event: "message", Data: "\n\nx"}},
+		},
+		{
+			name:  "no space after colon",
+			input: "data:x\n\n",
+			want:  []Event{{Event: "message", Data: "x"}},
+		},
+		{
+			name:  "comments",
+			input: ": keep-alive\n\n: ping\ndata: a\n: inside\n\n",
+			want:  []Event{{Event: "message", Data: "a"}},
+		},
+		{
+			name:  "event without data is not dispatched",
+			input: "event: ping\n\ndata: a\n\n",
+			want:  []Event{{Event: "message", Data: "a"}},
+		},
+		{
+			name:  "id and retry",
+			input: "id: 1\nretry: 3000\ndata: a\n\ndata: b\n\nid: 2\nretry: soon\ndata: c\n\n",
+			want: []Event{
+				{Event: "message", Data: "a", Id: "1", Retry: 3000},
+				{Event: "message", Data: "b", Id: "1"},
+				{Event: "message", Data: "c", Id: "2"},
+			},
+		},
+		{
+			name:  "unknown fields",
+			input: "foo: bar\ndata: a\n\n",
+			want:  []Event{{Event: "message", Data: "a"}},
+		},
+		{
+			name:  "truncated final event",
+			input: "data: a\n\ndata: b",
+			want:  []Event{{Event: "message", Data: "a"}},
+		},
+		{
+			name:  "truncated after last line",
+			input: "data: a\n\ndata: b\n",
+			want:  []Event{{Event: "message", Data: "a"}},
+		},
+		{
+			name:  "done",
+			input: "data: {\"id\":\"1\"}\n\ndata: [DONE]\n\n",
+			want:  []Event{{Event: "message", Data: "{\"id\":\"1\"}"}, {Event: "message", Data: "[DONE]"}},
+		},
+		{
+			name:  "error event",
+			input: "event: error\ndata: {\"error\":{\"message\":\"overloaded\"}}\n\n",
+			want:  []Event{{Event: "error", Data: "{\"error\":{\"message\":\"overloaded\"}}"}},
+		},
+		{
+			name:  "empty stream",
+			input: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// reading one byte at a time splits CRLF across reads
+			for name, r := range map[string]io.Reader{
+				"whole":    strings.NewReader(tt.input),
+				"one byte": iotest.OneByteReader(strings.NewReader(tt.input)),
+			} {
+				got, err := readAll(NewReader(r))
+				if !errors.Is(err, io.EOF) {
+					t.Errorf("%s: err = %v, want io.EOF", name, err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s: got %+v, want %+v", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	r := NewReader(io.MultiReader(strings.NewReader("data: a\n\ndata: b\n"), iotest.ErrReader(readErr)))
+
+	got, err := readAll(r)
+	if !errors.Is(err, readErr) {
+		t.Fatalf("err = %v, want %v", err, readErr)
+	}
+	if len(got) != 1 || got[0].Data != "a" {
+		t.Errorf("got %+v, want the first event only", got)
+	}
+}
+
+func TestReaderLineTooLong(t *testing.T) {
+	r := NewReader(strings.NewReader("data: " + strings.Repeat("x", maxLineSize) + "\n\n"))
+
+	if _, err := r.Next(); err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("err = %v, want a line too long error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package endpoint
+
+import (
+	"encoding/json"
+)
+
+// JobStatus is the state of a job as reported by RunPod. Values not listed
+// below are kept as received.
+type JobStatus string
+
+const (
+	JobStatusInQueue    JobStatus = "IN_QUEUE"
+	JobStatusInProgress JobStatus = "IN_PROGRESS"
+	JobStatusCompleted  JobStatus = "COMPLETED"
+	JobStatusFailed     JobStatus = "FAILED"
+	JobStatusCancelled  JobStatus = "CANCELLED"
+	JobStatusTimedOut   JobStatus = "TIMED_OUT"
+)
+
+// IsTerminal reports whether the job is finished and its status will not
+// change anymore.
+func (s JobStatus) IsTerminal() bool {
+	switch s {
+	case JobStatusCompleted, JobStatusFailed, JobStatusCancelled, JobStatusTimedOut:
+		return true
+	}
+	return false
+}
+
+// IsSuccess reports whether the job completed successfully.
+func (s JobStatus) IsSuccess() bool {
+	return s == JobStatusCompleted
+}
+
+// IsKnown reports whether s is one of the statuses listed above.
+func (s JobStatus) IsKnown() bool {
+	return s == JobStatusInQueue || s == JobStatusInProgress || s.IsTerminal()
+}
+
+func (s JobStatus) String() string {
+	return string(s)
+}
+
+// UnmarshalJSON accepts any value, a status that is not a string being
+// kept as its JSON text, so a new status never fails a whole response.
+func (s *JobStatus) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		*s = JobStatus(data)
+		return nil
+	}
+	*s = JobStatus(v)
+	return nil
+}
+
+// isTerminal reports whether status is set and terminal.
+func isTerminal(status *JobStatus) bool {
+	return status != nil && status.IsTerminal()
+}
+
+func statusString(status *JobStatus) string {
+	if status == nil {
+		return ""
+	}
+	return string(*status)
+}
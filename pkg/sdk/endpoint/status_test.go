@@ -0,0 +1,78 @@
+package endpoint_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+func TestJobStatus(t *testing.T) {
+	tests := []struct {
+		status                   endpoint.JobStatus
+		terminal, success, known bool
+	}{
+		{endpoint.JobStatusInQueue, false, false, true},
+		{endpoint.JobStatusInProgress, false, false, true},
+		{endpoint.JobStatusCompleted, true, true, true},
+		{endpoint.JobStatusFailed, true, false, true},
+		{endpoint.JobStatusCancelled, true, false, true},
+		{endpoint.JobStatusTimedOut, true, false, true},
+		{"PAUSED", false, false, false},
+		{"completed", false, false, false},
+		{"", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsTerminal(); got != tt.terminal {
+				t.Errorf("IsTerminal = %v, want %v", got, tt.terminal)
+			}
+			if got := tt.status.IsSuccess(); got != tt.success {
+				t.Errorf("IsSuccess = %v, want %v", got, tt.success)
+			}
+			if got := tt.status.IsKnown(); got != tt.known {
+				t.Errorf("IsKnown = %v, want %v", got, tt.known)
+			}
+			if got := tt.status.String(); got != string(tt.status) {
+				t.Errorf("String = %q, want %q", got, tt.status)
+			}
+		})
+	}
+}
+
+func TestJobStatusUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *endpoint.JobStatus
+	}{
+		{"known", `{"status":"COMPLETED"}`, statusPtr(endpoint.JobStatusCompleted)},
+		{"in queue", `{"status":"IN_QUEUE"}`, statusPtr(endpoint.JobStatusInQueue)},
+		{"unknown string", `{"status":"PAUSED"}`, statusPtr("PAUSED")},
+		{"number", `{"status":42}`, statusPtr("42")},
+		{"object", `{"status":{"code":1}}`, statusPtr(`{"code":1}`)},
+		{"null", `{"status":null}`, nil},
+		{"missing", `{}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output endpoint.StatusOutput
+			if err := json.Unmarshal([]byte(tt.body), &output); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			switch {
+			case tt.want == nil && output.Status != nil:
+				t.Errorf("status = %q, want nil", *output.Status)
+			case tt.want != nil && (output.Status == nil || *output.Status != *tt.want):
+				t.Errorf("status = %v, want %q", output.Status, *tt.want)
+			}
+			if output.Status != nil && output.Status.IsTerminal() && !output.Status.IsKnown() {
+				t.Errorf("unknown status %q is terminal", *output.Status)
+			}
+		})
+	}
+}
+
+func statusPtr(s endpoint.JobStatus) *endpoint.JobStatus {
+	return &s
+}
@@ -0,0 +1,231 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func (ep *Endpoint) Stream(input *StreamInput, outputChan chan<- StreamResult) error {
+	return ep.StreamWithContext(context.Background(), input, outputChan)
+}
+
+// StreamWithContext is like Stream but stops polling as soon as ctx is done.
+// outputChan is closed when the call returns.
+func (ep *Endpoint) StreamWithContext(ctx context.Context, input *StreamInput, outputChan chan<- StreamResult) error {
+	defer func() {
+		if outputChan != nil {
+			close(outputChan)
+		}
+	}()
+
+	reader, err := ep.OpenStream(ctx, input)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for reader.Next() {
+		select {
+		case outputChan <- reader.Result():
+		case <-reader.ctx.Done():
+			// set the error so Close reports the stream as failed
+			reader.err = reader.ctxErr()
+			return reader.err
+		}
+	}
+	return reader.Err()
+}
+
+// StreamReader reads the stream output of a job. The caller owns its
+// lifecycle: iterate with Next, then check Err and Final, and Close it to
+// release its resources.
+//
+//	reader, err := endpoint.OpenStream(ctx, &rpEndpoint.StreamInput{Id: id})
+//	if err != nil { ... }
+//	defer reader.Close()
+//	for reader.Next() {
+//		fmt.Println(reader.Result())
+//	}
+//	if err := reader.Err(); err != nil { ... }
+//	fmt.Println(*reader.Final().Status)
+type StreamReader struct {
+	ep     *Endpoint
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	id         *string
+	url        string
+	reqTimeout int
+
+	polls  int
+	last   time.Time
+	status string
+	buf    []StreamResult
+	cur    StreamResult
+	final  *StreamOutput
+	err    error
+	closed bool
+	end    func(result interface{}, err error)
+}
+
+// OpenStream starts reading the stream output of a job. No request is sent
+// until the first call to Next.
+func (ep *Endpoint) OpenStream(ctx context.Context, input *StreamInput) (*StreamReader, error) {
+	input, err := prepare(ep, input)
+	if err != nil {
+		return nil, err
+	}
+
+	follow := *input.Follow
+
+	wait := 90 * 1000
+	var reqTimeout int
+	timeout := *input.Timeout
+	if follow {
+		timeout = *input.PollWait
+	}
+
+	if timeout >= 90 {
+		reqTimeout = 90 + 2
+	} else {
+		wait = timeout * 1000
+		reqTimeout = timeout + 2
+	}
+
+	queryParams := url.Values{}
+	queryParams.Add("wait", strconv.Itoa(wait))
+
+	baseURL := *ep.EndpointUrl + "/" + *ep.EndpointId + "/stream/" + *input.Id
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("url parse error: %w", err)
+	}
+	u.RawQuery = queryParams.Encode()
+
+	ctx, end := ep.startOperation(ctx, OperationStream, input.Id)
+	var streamCtx context.Context
+	var cancel context.CancelFunc
+	switch {
+	case !follow:
+		streamCtx, cancel = context.WithTimeout(ctx, time.Duration(timeout+3)*time.Second)
+	case input.MaxDuration != nil:
+		streamCtx, cancel = context.WithTimeout(ctx, time.Duration(*input.MaxDuration)*time.Second)
+	default:
+		streamCtx, cancel = context.WithCancel(ctx)
+	}
+	return &StreamReader{
+		ep:         ep,
+		parent:     ctx,
+		ctx:        streamCtx,
+		cancel:     cancel,
+		id:         input.Id,
+		url:        u.String(),
+		reqTimeout: reqTimeout,
+		end:        end,
+	}, nil
+}
+
+// Next advances to the next stream result, polling the API as needed. It
+// returns false once the job reached a terminal status or an error occurred.
+func (r *StreamReader) Next() bool {
+	for {
+		if len(r.buf) > 0 {
+			r.cur, r.buf = r.buf[0], r.buf[1:]
+			return true
+		}
+		if r.final != nil || r.err != nil || r.closed {
+			r.finish()
+			return false
+		}
+		if r.ctx.Err() != nil {
+			r.err = r.ctxErr()
+			r.finish()
+			return false
+		}
+		// /stream answers right away while the job is queued, so space the
+		// polls like pollStatus does
+		if !r.last.IsZero() && sleepContext(r.ctx, time.Until(r.last.Add(minPollDelay))) != nil {
+			r.err = r.ctxErr()
+			r.finish()
+			return false
+		}
+		r.last = time.Now()
+		r.polls++
+		r.ep.logPoll(r.ctx, OperationStream, r.id, r.polls)
+		result, err := streamApiCall(r.ctx, r.ep, &r.url, r.id, &r.reqTimeout)
+		if err != nil {
+			if r.parent.Err() != nil {
+				err = r.parent.Err()
+			}
+			r.err = err
+			r.finish()
+			return false
+		}
+		r.ep.logTransition(r.ctx, r.id, r.status, result.Status)
+		if result.Status != nil {
+			r.status = string(*result.Status)
+		}
+		r.buf = result.Stream
+		if isTerminal(result.Status) {
+			r.final = &result
+		}
+	}
+}
+
+// Result returns the stream result read by the last call to Next.
+func (r *StreamReader) Result() StreamResult {
+	return r.cur
+}
+
+// Err returns the error that stopped Next, if any. A job that ended FAILED
+// is not an error; check Final instead.
+func (r *StreamReader) Err() error {
+	return r.err
+}
+
+// Final returns the last response of the stream, carrying the job's terminal
+// status. It is nil until the job reached a terminal status.
+func (r *StreamReader) Final() *StreamOutput {
+	return r.final
+}
+
+// Close stops the reader. Pending results are discarded.
+func (r *StreamReader) Close() error {
+	r.cancel()
+	r.buf = nil
+	r.closed = true
+	r.finish()
+	return nil
+}
+
+// finish ends the operation started by OpenStream, once.
+func (r *StreamReader) finish() {
+	if r.end != nil {
+		r.end(r.final, r.err)
+		r.end = nil
+	}
+}
+
+func (r *StreamReader) ctxErr() error {
+	if err := r.parent.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("ctx %w", ErrTimeout)
+}
+
+func streamApiCall(ctx context.Context, ep *Endpoint, url *string, id *string, reqTimeout *int) (StreamOutput, error) {
+	var result StreamOutput
+	_, err := ep.getApiResponse(ctx, apiRequestInput{op: OperationStream, method: "POST", url: url, token: ep.apiKey, timeout: reqTimeout, jobId: id, result: &result})
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("ctx %w", ErrTimeout)
+		}
+		return result, err
+	}
+	return result, nil
+}
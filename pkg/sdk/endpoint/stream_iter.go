@@ -0,0 +1,71 @@
+//go:build go1.23
+
+package endpoint
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over the remaining stream results. The final
+// element carries the error that stopped the reader, if any. The reader is
+// closed when the loop ends.
+//
+//	for result, err := range reader.All() {
+//		if err != nil { ... }
+//		fmt.Println(result)
+//	}
+func (r *StreamReader) All() iter.Seq2[StreamResult, error] {
+	return func(yield func(StreamResult, error) bool) {
+		defer r.Close()
+		for r.Next() {
+			if !yield(r.Result(), nil) {
+				return
+			}
+		}
+		if err := r.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// StreamSeq is a shorthand for OpenStream followed by All.
+func (ep *Endpoint) StreamSeq(ctx context.Context, input *StreamInput) iter.Seq2[StreamResult, error] {
+	return func(yield func(StreamResult, error) bool) {
+		reader, err := ep.OpenStream(ctx, input)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		reader.All()(yield)
+	}
+}
+
+// All returns an iterator over the remaining chunks. The final element
+// carries the error that stopped the stream, if any. The stream is closed
+// when the loop ends.
+func (s *ChatCompletionStream) All() iter.Seq2[ChatCompletionChunk, error] {
+	return func(yield func(ChatCompletionChunk, error) bool) {
+		defer s.Close()
+		for s.Next() {
+			if !yield(s.Chunk(), nil) {
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(ChatCompletionChunk{}, err)
+		}
+	}
+}
+
+// ChatCompletionSeq is a shorthand for ChatCompletionStream followed by All.
+func (c *OpenAI) ChatCompletionSeq(ctx context.Context, input *ChatCompletionInput) iter.Seq2[ChatCompletionChunk, error] {
+	return func(yield func(ChatCompletionChunk, error) bool) {
+		stream, err := c.ChatCompletionStream(ctx, input)
+		if err != nil {
+			yield(ChatCompletionChunk{}, err)
+			return
+		}
+		stream.All()(yield)
+	}
+}
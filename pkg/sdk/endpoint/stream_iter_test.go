@@ -0,0 +1,143 @@
+//go:build go1.23
+
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+func TestStreamReaderAll(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	chunks := []interface{}{"a", "b", "c"}
+	srv.Script = script(endpointtest.Script{ExecutionTime: 60 * time.Millisecond, Chunks: chunks})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	reader, err := ep.OpenStream(context.Background(), &endpoint.StreamInput{Id: run.Id, Follow: sdk.Bool(true), PollWait: sdk.Int(1)})
+	if err != nil {
+		t.Fatalf("OpenStream: %v", err)
+	}
+	var got []interface{}
+	for result, err := range reader.All() {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		got = append(got, result["output"])
+	}
+	if len(got) != len(chunks) {
+		t.Fatalf("got %v, want %v", got, chunks)
+	}
+	for i := range chunks {
+		if got[i] != chunks[i] {
+			t.Errorf("chunk %d = %v, want %v", i, got[i], chunks[i])
+		}
+	}
+	if final := reader.Final(); final == nil || *final.Status != endpoint.JobStatusCompleted {
+		t.Errorf("final = %+v, want a completed status", final)
+	}
+}
+
+func TestStreamReaderAllBreak(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{Hang: true, Chunks: []interface{}{"a", "b", "c"}})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	reader, err := ep.OpenStream(context.Background(), &endpoint.StreamInput{Id: run.Id, Follow: sdk.Bool(true), PollWait: sdk.Int(1)})
+	if err != nil {
+		t.Fatalf("OpenStream: %v", err)
+	}
+	n := 0
+	for _, err := range reader.All() {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatalf("read %d results, want 1", n)
+	}
+
+	// the loop closed the reader, so the buffered chunks are dropped and no
+	// more polls are sent
+	polls := srv.Requests("stream")
+	if reader.Next() {
+		t.Errorf("Next = true after the loop ended, want a closed reader")
+	}
+	if err := reader.Err(); err != nil {
+		t.Errorf("Err = %v, want nil", err)
+	}
+	if got := srv.Requests("stream"); got != polls {
+		t.Errorf("stream requests = %d after the loop ended, want %d", got, polls)
+	}
+}
+
+func TestStreamReaderAllError(t *testing.T) {
+	srv, ep := newTestEndpoint(t, func(opt *endpoint.Option) { opt.RetryPolicy = endpoint.NoRetry() })
+	srv.Script = script(endpointtest.Script{Hang: true})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	srv.FailNext("stream", http.StatusUnauthorized, 1)
+	n := 0
+	var last error
+	for _, err := range ep.StreamSeq(context.Background(), &endpoint.StreamInput{Id: run.Id, Follow: sdk.Bool(true), PollWait: sdk.Int(1)}) {
+		n++
+		last = err
+	}
+	var apiErr *endpoint.APIError
+	if n != 1 || !errors.As(last, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got %d elements ending with %v, want a single 401 APIError", n, last)
+	}
+}
+
+func TestStreamSeq(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	chunks := []interface{}{"a", "b", "c"}
+	srv.Script = script(endpointtest.Script{ExecutionTime: 60 * time.Millisecond, Chunks: chunks})
+
+	run, err := ep.Run(&endpoint.RunInput{JobInput: jobInput()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	var got []interface{}
+	for result, err := range ep.StreamSeq(context.Background(), &endpoint.StreamInput{Id: run.Id, Follow: sdk.Bool(true), PollWait: sdk.Int(1)}) {
+		if err != nil {
+			t.Fatalf("StreamSeq: %v", err)
+		}
+		got = append(got, result["output"])
+	}
+	if len(got) != len(chunks) {
+		t.Errorf("got %v, want %v", got, chunks)
+	}
+}
+
+func TestStreamSeqInvalidInput(t *testing.T) {
+	_, ep := newTestEndpoint(t, nil)
+
+	n := 0
+	for result, err := range ep.StreamSeq(context.Background(), nil) {
+		n++
+		if result != nil || !errors.Is(err, sdk.ErrInvalidInput) {
+			t.Errorf("got (%v, %v), want the validation error", result, err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("got %d elements, want 1", n)
+	}
+}
@@ -0,0 +1,49 @@
+package endpoint_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/config"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+func TestStreamFollowPacing(t *testing.T) {
+	// like the API for a queued job, answer every poll right away with no output
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		io.WriteString(w, `{"status":"IN_QUEUE"}`)
+	}))
+	defer srv.Close()
+	ep, err := endpoint.New(&config.Config{ApiKey: sdk.String("key")}, &endpoint.Option{
+		EndpointId:  sdk.String("queued"),
+		EndpointUrl: sdk.String(srv.URL),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	const window = 550 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), window)
+	defer cancel()
+	reader, err := ep.OpenStream(ctx, &endpoint.StreamInput{Id: sdk.String("job"), Follow: sdk.Bool(true)})
+	if err != nil {
+		t.Fatalf("OpenStream: %v", err)
+	}
+	defer reader.Close()
+	for reader.Next() {
+	}
+	if reader.Err() == nil {
+		t.Fatalf("Err = nil, want the ctx error")
+	}
+	if got := polls.Load(); got < 3 || got > 6 {
+		t.Errorf("sent %d stream polls in %s, want between 3 and 6", got, window)
+	}
+}
@@ -0,0 +1,168 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// TypedEndpoint wraps an Endpoint so job input is marshalled from In and the
+// job output is unmarshalled into Out.
+type TypedEndpoint[In, Out any] struct {
+	Endpoint *Endpoint
+}
+
+// TypedOutput is the typed counterpart of StatusOutput.
+type TypedOutput[Out any] struct {
+	DelayTime     *int       `json:"delayTime,omitempty"`
+	Error         *string    `json:"error,omitempty"`
+	ExecutionTime *int       `json:"executionTime,omitempty"`
+	Id            *string    `json:"id,omitempty"`
+	Output        *Out       `json:"output,omitempty"`
+	Retries       *int       `json:"retries,omitempty"`
+	Status        *JobStatus `json:"status,omitempty"`
+}
+
+// DecodeError is returned when a job input or output does not match the
+// Go type it is converted from or to.
+type DecodeError struct {
+	JobId string
+	// Type is the Go type the value was converted from or to.
+	Type string
+	// Raw is the JSON that failed to decode.
+	Raw json.RawMessage
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	if e.JobId != "" {
+		return fmt.Sprintf("job %s: cannot decode output into %s: %s", e.JobId, e.Type, e.Err)
+	}
+	return fmt.Sprintf("cannot convert %s: %s", e.Type, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func NewTyped[In, Out any](ep *Endpoint) *TypedEndpoint[In, Out] {
+	return &TypedEndpoint[In, Out]{Endpoint: ep}
+}
+
+// Run submits in as the job input. Fields of input other than
+// JobInput.Input, such as the policy or webhook, are kept; input may be nil.
+func (t *TypedEndpoint[In, Out]) Run(ctx context.Context, in In, input *RunInput) (*RunOutput, error) {
+	var req RunInput
+	if input != nil {
+		req = *input
+	}
+	job, err := typedJobInput(in, req.JobInput)
+	if err != nil {
+		return nil, err
+	}
+	req.JobInput = job
+	return t.Endpoint.RunWithContext(ctx, &req)
+}
+
+// RunSync submits in as the job input and waits for the typed output.
+// input may be nil.
+func (t *TypedEndpoint[In, Out]) RunSync(ctx context.Context, in In, input *RunSyncInput) (*TypedOutput[Out], error) {
+	var req RunSyncInput
+	if input != nil {
+		req = *input
+	}
+	job, err := typedJobInput(in, req.JobInput)
+	if err != nil {
+		return nil, err
+	}
+	req.JobInput = job
+	output, err := t.Endpoint.RunSyncWithContext(ctx, &req)
+	return toTyped[Out]((*StatusOutput)(output), err)
+}
+
+func (t *TypedEndpoint[In, Out]) Status(ctx context.Context, input *StatusInput) (*TypedOutput[Out], error) {
+	output, err := t.Endpoint.StatusWithContext(ctx, input)
+	return toTyped[Out](output, err)
+}
+
+func (t *TypedEndpoint[In, Out]) StatusSync(ctx context.Context, input *StatusSyncInput) (*TypedOutput[Out], error) {
+	output, err := t.Endpoint.StatusSyncWithContext(ctx, input)
+	return toTyped[Out]((*StatusOutput)(output), err)
+}
+
+// EncodeInput converts v into the map used by JobInput.Input. v must encode
+// to a JSON object.
+func EncodeInput(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, &DecodeError{Type: typeName(v), Err: err}
+	}
+	var input map[string]interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, &DecodeError{Type: typeName(v), Raw: data, Err: fmt.Errorf("input must encode to a JSON object: %w", err)}
+	}
+	return input, nil
+}
+
+// DecodeOutput unmarshals a job output, as found on the Output field of the
+// status structs, into Out. It returns nil when the job has no output.
+func DecodeOutput[Out any](output *interface{}) (*Out, error) {
+	typed, err := decodeTyped[Out](nil, output)
+	return typed.Output, err
+}
+
+func typedJobInput(in interface{}, template *JobInput) (*JobInput, error) {
+	input, err := EncodeInput(in)
+	if err != nil {
+		return nil, err
+	}
+	var job JobInput
+	if template != nil {
+		job = *template
+	}
+	job.Input = input
+	return &job, nil
+}
+
+// toTyped decodes the output of a status call, keeping the call error first.
+func toTyped[Out any](output *StatusOutput, err error) (*TypedOutput[Out], error) {
+	if output == nil {
+		return nil, err
+	}
+	typed, decodeErr := decodeTyped[Out](output.Id, output.Output)
+	typed.DelayTime, typed.Error, typed.ExecutionTime = output.DelayTime, output.Error, output.ExecutionTime
+	typed.Id, typed.Retries, typed.Status = output.Id, output.Retries, output.Status
+	if err != nil {
+		return typed, err
+	}
+	return typed, decodeErr
+}
+
+func decodeTyped[Out any](id *string, output *interface{}) (*TypedOutput[Out], error) {
+	typed := &TypedOutput[Out]{}
+	if output == nil || *output == nil {
+		return typed, nil
+	}
+	data, err := json.Marshal(*output)
+	if err != nil {
+		return typed, fmt.Errorf("json marshal error: %w", err)
+	}
+	var out Out
+	if err := json.Unmarshal(data, &out); err != nil {
+		decodeErr := &DecodeError{Type: reflect.TypeOf(&out).Elem().String(), Raw: data, Err: err}
+		if id != nil {
+			decodeErr.JobId = *id
+		}
+		return typed, decodeErr
+	}
+	typed.Output = &out
+	return typed, nil
+}
+
+func typeName(v interface{}) string {
+	if t := reflect.TypeOf(v); t != nil {
+		return t.String()
+	}
+	return "<nil>"
+}
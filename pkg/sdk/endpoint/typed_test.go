@@ -0,0 +1,122 @@
+package endpoint_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint/endpointtest"
+)
+
+type prompt struct {
+	Prompt string `json:"prompt"`
+	Steps  int    `json:"steps,omitempty"`
+}
+
+type image struct {
+	URL   string `json:"image_url"`
+	Steps int    `json:"steps"`
+}
+
+// echoImage answers every job with an image built from its prompt.
+func echoImage(input *endpoint.JobInput) endpointtest.Script {
+	return endpointtest.Script{Output: map[string]interface{}{
+		"image_url": "https://example.com/" + input.Input["prompt"].(string) + ".png",
+		"steps":     input.Input["steps"],
+	}}
+}
+
+func TestTypedRunSync(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = echoImage
+
+	typed := endpoint.NewTyped[prompt, image](ep)
+	output, err := typed.RunSync(context.Background(), prompt{Prompt: "fox", Steps: 30}, &endpoint.RunSyncInput{Timeout: sdk.Int(5)})
+	if err != nil {
+		t.Fatalf("RunSync: %v", err)
+	}
+	if output.Status == nil || *output.Status != endpoint.JobStatusCompleted || output.Id == nil {
+		t.Fatalf("output = %+v, want a completed job", output)
+	}
+	want := image{URL: "https://example.com/fox.png", Steps: 30}
+	if output.Output == nil || *output.Output != want {
+		t.Errorf("output = %+v, want %+v", output.Output, want)
+	}
+
+	status, err := typed.Status(context.Background(), &endpoint.StatusInput{Id: output.Id})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Output == nil || *status.Output != want {
+		t.Errorf("status output = %+v, want %+v", status.Output, want)
+	}
+}
+
+func TestTypedRunKeepsJobInput(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	var received *endpoint.JobInput
+	srv.Script = func(input *endpoint.JobInput) endpointtest.Script {
+		received = input
+		return endpointtest.Script{}
+	}
+
+	typed := endpoint.NewTyped[prompt, image](ep)
+	_, err := typed.Run(context.Background(), prompt{Prompt: "fox"}, &endpoint.RunInput{
+		JobInput: &endpoint.JobInput{Webhook: sdk.String("https://example.com/hook"), Input: map[string]interface{}{"old": true}},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if received == nil || received.Webhook == nil || *received.Webhook != "https://example.com/hook" {
+		t.Fatalf("received %+v, want the webhook kept", received)
+	}
+	if received.Input["prompt"] != "fox" || received.Input["old"] != nil {
+		t.Errorf("input = %v, want only the typed input", received.Input)
+	}
+}
+
+func TestTypedOutputMismatch(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+	srv.Script = script(endpointtest.Script{Output: []interface{}{"not", "an", "image"}})
+
+	typed := endpoint.NewTyped[prompt, image](ep)
+	output, err := typed.RunSync(context.Background(), prompt{Prompt: "fox"}, nil)
+	var decodeErr *endpoint.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("err = %v, want a DecodeError", err)
+	}
+	if decodeErr.Type != "endpoint_test.image" || decodeErr.JobId == "" || string(decodeErr.Raw) != `["not","an","image"]` {
+		t.Errorf("DecodeError = %+v", decodeErr)
+	}
+	// the job itself is still reported
+	if output == nil || output.Status == nil || *output.Status != endpoint.JobStatusCompleted || output.Output != nil {
+		t.Errorf("output = %+v, want the completed job without output", output)
+	}
+}
+
+func TestTypedInputNotAnObject(t *testing.T) {
+	srv, ep := newTestEndpoint(t, nil)
+
+	typed := endpoint.NewTyped[string, image](ep)
+	_, err := typed.RunSync(context.Background(), "fox", nil)
+	var decodeErr *endpoint.DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Type != "string" {
+		t.Fatalf("err = %v, want a DecodeError for string", err)
+	}
+	if got := srv.Requests("runsync") + srv.Requests("run"); got != 0 {
+		t.Errorf("sent %d requests, want none", got)
+	}
+}
+
+func TestDecodeOutput(t *testing.T) {
+	if out, err := endpoint.DecodeOutput[image](nil); out != nil || err != nil {
+		t.Errorf("DecodeOutput(nil) = %v, %v, want nil, nil", out, err)
+	}
+	var raw interface{} = map[string]interface{}{"image_url": "u", "steps": 2.0}
+	out, err := endpoint.DecodeOutput[image](&raw)
+	if err != nil || out == nil || *out != (image{URL: "u", Steps: 2}) {
+		t.Errorf("DecodeOutput = %+v, %v", out, err)
+	}
+}
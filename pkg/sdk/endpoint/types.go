@@ -1,7 +1,27 @@
 package endpoint
 
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk/ratelimit"
+)
+
 type Endpoint struct {
 	apiKey *string
+	client *http.Client
+	doer   Doer
+	hooks  []OperationHook
+	logger *slog.Logger
+
+	requestTimeoutDefault *int
+	syncTimeoutDefault    *int
+
+	defaultPollStrategy PollStrategy
+
+	cancelOnAbandon bool
+	cancelTimeout   time.Duration
 
 	// EndpointId where the job will be executed
 	EndpointId  *string
@@ -10,7 +30,46 @@ type Endpoint struct {
 
 type Option struct {
 	EndpointId  *string `json:"endpointId" required:"true"`
-	EndpointUrl *string `json:"endpointUrl" default:"https://api.runpod.ai/v2/"`
+	EndpointUrl *string `json:"endpointUrl" default:"https://api.runpod.ai/v2"`
+
+	// HTTPClient overrides config.Config.HTTPClient for this endpoint.
+	HTTPClient *http.Client `json:"-"`
+	// Transport overrides config.Config.Transport for this endpoint. It is
+	// ignored when an HTTPClient is set.
+	Transport http.RoundTripper `json:"-"`
+
+	// RetryPolicy controls retries of failed requests. Defaults to
+	// DefaultRetryPolicy, use NoRetry to disable.
+	RetryPolicy *RetryPolicy `json:"-"`
+
+	// RateLimiter overrides config.Config.RateLimiter for this endpoint.
+	RateLimiter ratelimit.Limiter `json:"-"`
+
+	// PollStrategy paces the status polls of RunSync, StatusSync and
+	// Job.Wait. Defaults to DefaultPollStrategy.
+	PollStrategy PollStrategy `json:"-"`
+
+	// CancelOnAbandon cancels the job a RunSync, StatusSync or Job.Wait
+	// call was waiting for when the caller's context ends first, so it does
+	// not keep a worker busy. The call then returns an *AbandonedError.
+	// RunSync submits through /run instead of /runsync when it is set, so the
+	// job id is known before waiting.
+	CancelOnAbandon *bool `json:"-" default:"false"`
+	// CancelTimeout bounds, in seconds, the cancel request sent for an
+	// abandoned job.
+	CancelTimeout *int `json:"-" default:"5" min:"1"`
+
+	// Logger overrides config.Config.Logger for this endpoint.
+	Logger *slog.Logger `json:"-"`
+
+	// Middleware wraps every call made by the endpoint, the first one being
+	// the outermost.
+	Middleware []Middleware `json:"-"`
+	// AttemptMiddleware wraps every attempt of a call, inside the retry and
+	// rate limit middleware, e.g. to trace or time each HTTP request.
+	AttemptMiddleware []Middleware `json:"-"`
+	// OperationHooks are called around every operation, see OperationHook.
+	OperationHooks []OperationHook `json:"-"`
 }
 
 type RunInput struct {
@@ -18,15 +77,18 @@ type RunInput struct {
 	JobInput *JobInput
 
 	// RequestTimeout is the maximum time in seconds to wait for the request to complete
-	RequestTimeout *int `default:"3"`
+	RequestTimeout *int `default:"3" min:"1"`
 }
 
 type RunSyncInput struct {
 	// JobInput is the input payload for the job
 	JobInput *JobInput
 
-	// RequestTimeout is the maximum time in seconds to wait for the request to complete
-	Timeout *int `default:"120"`
+	// Timeout is the maximum time in seconds to wait for the job to complete
+	Timeout *int `default:"90" min:"1"`
+	// PollStrategy paces the status polls sent while the job is not done.
+	// Defaults to Option.PollStrategy.
+	PollStrategy PollStrategy
 }
 
 type JobInput struct {
@@ -51,8 +113,8 @@ type Policy struct {
 }
 
 type RunOutput struct {
-	Id     *string `json:"id,omitempty"`
-	Status *string `json:"status,omitempty"`
+	Id     *string    `json:"id,omitempty"`
+	Status *JobStatus `json:"status,omitempty"`
 }
 
 type RunSyncOutput struct {
@@ -62,20 +124,24 @@ type RunSyncOutput struct {
 	Id            *string      `json:"id,omitempty"`
 	Output        *interface{} `json:"output,omitempty"`
 	Retries       *int         `json:"retries,omitempty"`
-	Status        *string      `json:"status,omitempty"`
+	Status        *JobStatus   `json:"status,omitempty"`
 }
 
 type apiRequestInput struct {
+	op      Operation
 	method  string
 	url     *string
 	reqBody []byte
 	token   *string
 	timeout *int
+	jobId   *string
+	// result is what the response body is decoded into, if not nil
+	result interface{}
 }
 
 type StatusInput struct {
 	Id             *string `json:"id" required:"true"`
-	RequestTimeout *int    `default:"3"`
+	RequestTimeout *int    `default:"3" min:"1"`
 }
 
 type StatusOutput struct {
@@ -85,12 +151,14 @@ type StatusOutput struct {
 	Id            *string      `json:"id,omitempty"`
 	Output        *interface{} `json:"output,omitempty"`
 	Retries       *int         `json:"retries,omitempty"`
-	Status        *string      `json:"status,omitempty"`
+	Status        *JobStatus   `json:"status,omitempty"`
 }
 
 type StatusSyncInput struct {
 	Id      *string `json:"id" required:"true"`
-	Timeout *int    `default:"120"`
+	Timeout *int    `default:"90" min:"1"`
+	// PollStrategy paces the status polls. Defaults to Option.PollStrategy.
+	PollStrategy PollStrategy `json:"-"`
 }
 
 type StatusSyncOutput struct {
@@ -100,11 +168,11 @@ type StatusSyncOutput struct {
 	Id            *string      `json:"id,omitempty"`
 	Output        *interface{} `json:"output,omitempty"`
 	Retries       *int         `json:"retries,omitempty"`
-	Status        *string      `json:"status,omitempty"`
+	Status        *JobStatus   `json:"status,omitempty"`
 }
 
 type HealthInput struct {
-	RequestTimeout *int `default:"3"`
+	RequestTimeout *int `default:"3" min:"1"`
 }
 
 type HealthOutput struct {
@@ -129,7 +197,7 @@ type HealthJobOutput struct {
 }
 
 type PurgeQueueInput struct {
-	RequestTimeout *int `default:"3"`
+	RequestTimeout *int `default:"3" min:"1"`
 }
 
 type PurgeQueueOutput struct {
@@ -139,24 +207,36 @@ type PurgeQueueOutput struct {
 
 type CancelInput struct {
 	Id             *string `json:"id" required:"true"`
-	RequestTimeout *int    `default:"3"`
+	RequestTimeout *int    `default:"3" min:"1"`
 }
 
 type CancelOutput struct {
-	DelayTime     *int    `json:"delayTime,omitempty"`
-	Error         *string `json:"error,omitempty"`
-	ExecutionTime *int    `json:"executionTime,omitempty"`
-	Id            *string `json:"id,omitempty"`
-	Status        *string `json:"status,omitempty"`
+	DelayTime     *int       `json:"delayTime,omitempty"`
+	Error         *string    `json:"error,omitempty"`
+	ExecutionTime *int       `json:"executionTime,omitempty"`
+	Id            *string    `json:"id,omitempty"`
+	Status        *JobStatus `json:"status,omitempty"`
 }
 
 type StreamInput struct {
 	Id      *string `json:"id" required:"true"`
-	Timeout *int    `default:"120"`
+	Timeout *int    `default:"90" min:"1"`
+
+	// Follow keeps polling until the job reaches a terminal status instead of
+	// giving up after Timeout, so long generations stream end to end.
+	// Timeout is ignored when Follow is set.
+	Follow *bool `default:"false"`
+	// PollWait is how long, in seconds, each poll waits for new output when
+	// following. Capped at 90.
+	PollWait *int `default:"90" min:"1"`
+	// MaxDuration optionally bounds, in seconds, the whole stream when
+	// following. Without it the stream only ends with the job or the context.
+	MaxDuration *int `min:"1"`
 }
 
 type StreamResult map[string]interface{}
 type StreamOutput struct {
-	Status *string
-	Stream []StreamResult
+	Status *JobStatus     `json:"status,omitempty"`
+	Error  *string        `json:"error,omitempty"`
+	Stream []StreamResult `json:"stream,omitempty"`
 }
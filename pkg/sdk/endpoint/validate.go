@@ -0,0 +1,54 @@
+package endpoint
+
+import (
+	"reflect"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+)
+
+// prepare returns a copy of input whose nil fields are filled from the
+// endpoint's config, then from their default tags, or the input's
+// validation errors. The caller's input is never modified.
+func prepare[T any](ep *Endpoint, input *T) (*T, error) {
+	if input == nil {
+		return nil, &sdk.ValidationError{
+			Type:   reflect.TypeOf((*T)(nil)).Elem().Name(),
+			Fields: []sdk.FieldError{{Field: "input", Message: "is required"}},
+		}
+	}
+	in := *input
+	ep.applyConfigDefaults(&in)
+	if err := sdk.ApplyDefaults(&in); err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+// applyConfigDefaults sets the timeouts left nil to the ones of the config
+// the endpoint was built from.
+func (ep *Endpoint) applyConfigDefaults(input interface{}) {
+	var timeout, fallback **int
+	switch in := input.(type) {
+	case *RunInput:
+		timeout, fallback = &in.RequestTimeout, &ep.requestTimeoutDefault
+	case *StatusInput:
+		timeout, fallback = &in.RequestTimeout, &ep.requestTimeoutDefault
+	case *HealthInput:
+		timeout, fallback = &in.RequestTimeout, &ep.requestTimeoutDefault
+	case *PurgeQueueInput:
+		timeout, fallback = &in.RequestTimeout, &ep.requestTimeoutDefault
+	case *CancelInput:
+		timeout, fallback = &in.RequestTimeout, &ep.requestTimeoutDefault
+	case *RunSyncInput:
+		timeout, fallback = &in.Timeout, &ep.syncTimeoutDefault
+	case *StatusSyncInput:
+		timeout, fallback = &in.Timeout, &ep.syncTimeoutDefault
+	case *StreamInput:
+		timeout, fallback = &in.Timeout, &ep.syncTimeoutDefault
+	default:
+		return
+	}
+	if *timeout == nil {
+		*timeout = *fallback
+	}
+}
@@ -0,0 +1,226 @@
+// Package webhook receives the job completion callbacks RunPod sends to the
+// Webhook and WebhookV2 urls of a JobInput.
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+// TokenParam is the query parameter carrying the shared secret.
+const TokenParam = "token"
+
+// maxBodySize caps the size of a webhook payload.
+const maxBodySize = 32 << 20
+
+// Func handles a webhook payload. Returning an error answers RunPod with a
+// 500 so the delivery is retried.
+type Func func(ctx context.Context, output *endpoint.StatusOutput) error
+
+type Option struct {
+	// Secret, when set, must match the token query parameter of incoming
+	// requests. RunPod does not sign webhooks, so build the url passed as
+	// JobInput.Webhook with SignedURL to authenticate deliveries.
+	Secret *string
+	// DedupeTTL is how long, in seconds, a delivered job status is remembered
+	// to drop redeliveries. A negative value disables deduplication.
+	DedupeTTL *int `default:"3600"`
+}
+
+// Handler is an http.Handler dispatching RunPod webhooks to registered
+// callbacks. Deliveries are deduplicated by job id and status: a delivery
+// whose callbacks succeeded is acknowledged without calling them again, and
+// one arriving while the same delivery is still being handled is answered
+// with a 409 so RunPod sends it again later.
+type Handler struct {
+	secret    *string
+	dedupeTTL time.Duration
+
+	mu        sync.Mutex
+	handlers  map[endpoint.JobStatus][]Func
+	any       []Func
+	seen      map[string]time.Time
+	inFlight  map[string]bool
+	lastPrune time.Time
+}
+
+// claim results
+const (
+	claimed = iota
+	claimInFlight
+	claimDelivered
+)
+
+func New(input *Option) *Handler {
+	var option Option
+	if input != nil {
+		option = *input
+	}
+	// DedupeTTL only has a default tag, an error here is a bad tag
+	if err := sdk.ApplyDefaults(&option); err != nil {
+		panic(err)
+	}
+	return &Handler{
+		secret:    option.Secret,
+		dedupeTTL: time.Duration(*option.DedupeTTL) * time.Second,
+		handlers:  map[endpoint.JobStatus][]Func{},
+		seen:      map[string]time.Time{},
+		inFlight:  map[string]bool{},
+	}
+}
+
+// SignedURL adds secret to a webhook url so the Handler can authenticate it.
+func SignedURL(webhookURL string, secret string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("url parse error: %w", err)
+	}
+	query := u.Query()
+	query.Set(TokenParam, secret)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// On registers fn for payloads with the given job status, e.g.
+// endpoint.JobStatusCompleted.
+func (h *Handler) On(status endpoint.JobStatus, fn Func) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[status] = append(h.handlers[status], fn)
+}
+
+// OnAny registers fn for every payload.
+func (h *Handler) OnAny(fn Func) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.any = append(h.any, fn)
+}
+
+func (h *Handler) OnCompleted(fn Func) {
+	h.On(endpoint.JobStatusCompleted, fn)
+}
+
+func (h *Handler) OnFailed(fn Func) {
+	h.On(endpoint.JobStatusFailed, fn)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "io read error", http.StatusBadRequest)
+		return
+	}
+	var output endpoint.StatusOutput
+	if err := json.Unmarshal(body, &output); err != nil {
+		http.Error(w, "json decoder error", http.StatusBadRequest)
+		return
+	}
+	if output.Id == nil {
+		http.Error(w, "job id is required", http.StatusBadRequest)
+		return
+	}
+
+	key := *output.Id
+	if output.Status != nil {
+		key += "/" + string(*output.Status)
+	}
+	switch h.claim(key) {
+	case claimDelivered:
+		w.WriteHeader(http.StatusOK)
+		return
+	case claimInFlight:
+		// the first delivery may still fail, so this one must not be dropped
+		http.Error(w, "delivery in progress", http.StatusConflict)
+		return
+	}
+	delivered := false
+	defer func() { h.release(key, delivered) }()
+	for _, fn := range h.callbacks(output.Status) {
+		if err := fn(r.Context(), &output); err != nil {
+			http.Error(w, "handler error", http.StatusInternalServerError)
+			return
+		}
+	}
+	delivered = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	if h.secret == nil {
+		return true
+	}
+	token := r.URL.Query().Get(TokenParam)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(*h.secret)) == 1
+}
+
+func (h *Handler) callbacks(status *endpoint.JobStatus) []Func {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var fns []Func
+	if status != nil {
+		fns = append(fns, h.handlers[*status]...)
+	}
+	return append(fns, h.any...)
+}
+
+// claim marks key as in flight, unless it is already in flight or was
+// delivered.
+func (h *Handler) claim(key string) int {
+	if h.dedupeTTL < 0 {
+		return claimed
+	}
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if now.Sub(h.lastPrune) > h.dedupeTTL {
+		for k, t := range h.seen {
+			if now.Sub(t) > h.dedupeTTL {
+				delete(h.seen, k)
+			}
+		}
+		h.lastPrune = now
+	}
+	if h.inFlight[key] {
+		return claimInFlight
+	}
+	if t, ok := h.seen[key]; ok && now.Sub(t) <= h.dedupeTTL {
+		return claimDelivered
+	}
+	h.inFlight[key] = true
+	return claimed
+}
+
+// release ends the handling of key, remembering it if it was delivered so
+// redeliveries are dropped, or forgetting it so a failed delivery can be
+// retried.
+func (h *Handler) release(key string, delivered bool) {
+	if h.dedupeTTL < 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.inFlight, key)
+	if delivered {
+		h.seen[key] = time.Now()
+	}
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/runpod/go-sdk/pkg/sdk"
+	"github.com/runpod/go-sdk/pkg/sdk/endpoint"
+)
+
+const completed = `{"id":"job-1","status":"COMPLETED","output":"done"}`
+
+func deliver(h http.Handler, target string, body string) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+	return w.Code
+}
+
+func TestDispatch(t *testing.T) {
+	h := New(nil)
+	var completedCalls, failedCalls, anyCalls atomic.Int32
+	h.OnCompleted(func(ctx context.Context, output *endpoint.StatusOutput) error {
+		if *output.Id != "job-1" || (*output.Output).(string) != "done" {
+			t.Errorf("output = %+v", output)
+		}
+		completedCalls.Add(1)
+		return nil
+	})
+	h.OnFailed(func(context.Context, *endpoint.StatusOutput) error {
+		failedCalls.Add(1)
+		return nil
+	})
+	h.OnAny(func(context.Context, *endpoint.StatusOutput) error {
+		anyCalls.Add(1)
+		return nil
+	})
+
+	if code := deliver(h, "/", completed); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if completedCalls.Load() != 1 || failedCalls.Load() != 0 || anyCalls.Load() != 1 {
+		t.Errorf("calls: completed %d, failed %d, any %d", completedCalls.Load(), failedCalls.Load(), anyCalls.Load())
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	h := New(&Option{Secret: sdk.String("secret")})
+	signed, err := SignedURL("/hook", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{"get", http.MethodGet, signed, completed, http.StatusMethodNotAllowed},
+		{"no token", http.MethodPost, "/hook", completed, http.StatusUnauthorized},
+		{"wrong token", http.MethodPost, "/hook?token=guess", completed, http.StatusUnauthorized},
+		{"invalid json", http.MethodPost, signed, "{", http.StatusBadRequest},
+		{"no job id", http.MethodPost, signed, `{"status":"COMPLETED"}`, http.StatusBadRequest},
+		{"signed", http.MethodPost, signed, completed, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	h := New(nil)
+	var calls atomic.Int32
+	h.OnAny(func(context.Context, *endpoint.StatusOutput) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if code := deliver(h, "/", completed); code != http.StatusOK {
+			t.Fatalf("delivery %d: status = %d, want 200", i, code)
+		}
+	}
+	// a new status of the same job is another delivery
+	deliver(h, "/", `{"id":"job-1","status":"FAILED"}`)
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+
+	h = New(&Option{DedupeTTL: sdk.Int(-1)})
+	calls.Store(0)
+	h.OnAny(func(context.Context, *endpoint.StatusOutput) error {
+		calls.Add(1)
+		return nil
+	})
+	deliver(h, "/", completed)
+	deliver(h, "/", completed)
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls without dedupe = %d, want 2", got)
+	}
+}
+
+func TestFailedDeliveryIsRetried(t *testing.T) {
+	h := New(nil)
+	var calls atomic.Int32
+	h.OnCompleted(func(context.Context, *endpoint.StatusOutput) error {
+		if calls.Add(1) == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	if code := deliver(h, "/", completed); code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", code)
+	}
+	if code := deliver(h, "/", completed); code != http.StatusOK {
+		t.Fatalf("status of the retry = %d, want 200", code)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestConcurrentRedelivery(t *testing.T) {
+	h := New(nil)
+	started, finish := make(chan struct{}), make(chan error)
+	var calls atomic.Int32
+	h.OnCompleted(func(context.Context, *endpoint.StatusOutput) error {
+		if calls.Add(1) == 1 {
+			close(started)
+			return <-finish
+		}
+		return nil
+	})
+
+	first := make(chan int)
+	go func() { first <- deliver(h, "/", completed) }()
+	<-started
+
+	// the first delivery is still running and may fail, so the redelivery
+	// must not be acknowledged
+	if code := deliver(h, "/", completed); code != http.StatusConflict {
+		t.Fatalf("status of the concurrent redelivery = %d, want 409", code)
+	}
+	finish <- errors.New("database unavailable")
+	if code := <-first; code != http.StatusInternalServerError {
+		t.Fatalf("status of the first delivery = %d, want 500", code)
+	}
+
+	if code := deliver(h, "/", completed); code != http.StatusOK {
+		t.Fatalf("status of the later redelivery = %d, want 200", code)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestPanickingCallbackReleasesDelivery(t *testing.T) {
+	h := New(nil)
+	var calls atomic.Int32
+	h.OnCompleted(func(context.Context, *endpoint.StatusOutput) error {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	func() {
+		defer func() { recover() }()
+		deliver(h, "/", completed)
+	}()
+	if code := deliver(h, "/", completed); code != http.StatusOK {
+		t.Fatalf("status after a panic = %d, want 200", code)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/runpod/go-sdk/pkg/sdk
 
 go 1.21.3
+
+require github.com/BurntSushi/toml v1.4.0
@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBurst(t *testing.T) {
+	b := New(1, 5)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst of 5 took %s, want no wait", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait beyond the burst = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestMinimumBurst(t *testing.T) {
+	if b := New(1, 0); b.burst != 1 || b.tokens != 1 {
+		t.Errorf("burst %v, tokens %v, want 1 and 1", b.burst, b.tokens)
+	}
+}
+
+func TestRefill(t *testing.T) {
+	b := New(10, 3)
+	b.tokens = 0
+	start := b.last
+
+	b.refill(start.Add(100 * time.Millisecond))
+	if math.Abs(b.tokens-1) > 1e-9 {
+		t.Errorf("tokens after 100ms = %v, want 1", b.tokens)
+	}
+	b.refill(start.Add(time.Hour))
+	if b.tokens != 3 {
+		t.Errorf("tokens after an hour = %v, want the burst of 3", b.tokens)
+	}
+	// a clock going backwards adds nothing
+	b.tokens = 0
+	b.refill(start)
+	if b.tokens != 0 {
+		t.Errorf("tokens = %v, want 0", b.tokens)
+	}
+}
+
+func TestRate(t *testing.T) {
+	b := New(20, 1)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait %d: %v", i, err)
+		}
+	}
+	// one token from the burst, then 5 at 20 per second
+	if elapsed := time.Since(start); elapsed < 240*time.Millisecond || elapsed > time.Second {
+		t.Errorf("6 requests at 20/s with a burst of 1 took %s, want about 250ms", elapsed)
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	b := New(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait with a cancelled ctx = %v, want context.Canceled", err)
+	}
+	// the cancelled call took no token
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Wait returned %s after ctx was cancelled", elapsed)
+	}
+	// the token reserved by the cancelled call was given back
+	b.mu.Lock()
+	tokens := b.tokens
+	b.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("tokens = %v after a cancelled wait, want the reservation given back", tokens)
+	}
+}
+
+func TestZeroRate(t *testing.T) {
+	b := New(0, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait = %v, want context.DeadlineExceeded", err)
+	}
+	if b.tokens != 0 {
+		t.Errorf("tokens = %v, want 0", b.tokens)
+	}
+}
+
+func TestConcurrentWait(t *testing.T) {
+	const (
+		rate    = 500
+		burst   = 10
+		callers = 60
+	)
+	b := New(rate, burst)
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- b.Wait(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	// every caller beyond the burst waited for a token
+	want := time.Duration(float64(callers-burst) / rate * float64(time.Second))
+	if elapsed := time.Since(start); elapsed < want-10*time.Millisecond {
+		t.Errorf("%d callers took %s, want at least %s", callers, elapsed, want)
+	}
+}
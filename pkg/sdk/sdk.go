@@ -7,3 +7,7 @@ func String(v string) *string {
 func Int(v int) *int {
 	return &v
 }
+
+func Bool(v bool) *bool {
+	return &v
+}